@@ -0,0 +1,229 @@
+package gevent
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrAsyncQueueFull 异步派发队列已满
+	ErrAsyncQueueFull = errors.New("async dispatch queue full")
+
+	// ErrAsyncDispatcherStarted 异步派发器已启动
+	ErrAsyncDispatcherStarted = errors.New("async dispatcher already started")
+
+	// ErrAsyncDispatcherStopped 异步派发器已停止
+	ErrAsyncDispatcherStopped = errors.New("async dispatcher stopped")
+)
+
+// AsyncPartitioner 异步派发分区策略
+// 返回事件所属分区的键，键必须是可比较的值
+// 同一分区的事件由同一个工作协程按投递顺序派发，不同分区的事件可以并行派发
+type AsyncPartitioner[EventKind, EventValue comparable] func(evtId EventID[EventKind, EventValue]) interface{}
+
+// PartitionByKind 按事件类型分区，保证同类型事件按投递顺序派发
+func PartitionByKind[EventKind, EventValue comparable]() AsyncPartitioner[EventKind, EventValue] {
+	return func(evtId EventID[EventKind, EventValue]) interface{} { return evtId.Kind }
+}
+
+// PartitionByEventID 按事件ID分区，保证同ID事件按投递顺序派发
+func PartitionByEventID[EventKind, EventValue comparable]() AsyncPartitioner[EventKind, EventValue] {
+	return func(evtId EventID[EventKind, EventValue]) interface{} { return evtId }
+}
+
+// AsyncOptions 异步派发器选项
+type AsyncOptions[EventKind, EventValue comparable] struct {
+	QueueSize    int                                      // 事件队列容量，小于等于 0 时使用默认值；分区派发时为每个工作协程的队列容量
+	Workers      int                                      // 工作协程数量，小于等于 0 时使用默认值
+	ErrorHandler EventErrorHandler[EventKind, EventValue] // 错误处理器，在工作协程中被调用，为空时丢弃错误
+	Partitioner  AsyncPartitioner[EventKind, EventValue]  // 分区策略，为空时不保证事件的派发顺序
+}
+
+const (
+	defaultAsyncQueueSize = 1024 // 默认事件队列容量
+	defaultAsyncWorkers   = 1    // 默认工作协程数量
+)
+
+// 异步派发器状态
+const (
+	asyncStateCreated = iota // 已创建
+	asyncStateRunning        // 运行中
+	asyncStateStopped        // 已停止
+)
+
+// asyncEvent 等待异步派发的事件
+type asyncEvent[EventKind, EventValue comparable] struct {
+	evtId     EventID[EventKind, EventValue] // 事件ID
+	generator interface{}                    // 事件产生者
+	param     interface{}                    // 参数
+	partition *asyncPartition                // 所属分区，未分区时为空
+}
+
+// asyncPartition 分区状态
+// 分区内尚有未派发完成的事件时，后续事件会被投递到同一个工作协程
+type asyncPartition struct {
+	key     interface{} // 分区键
+	worker  int         // 负责该分区的工作协程
+	pending int         // 未派发完成的事件数量
+}
+
+// AsyncDispatcher 异步事件派发器
+// 在 ConcurrentDispatcher 的基础上，提供将事件投递到有界队列，由工作协程池异步派发的能力
+// 监听者的添加、移除以及同步派发，沿用 ConcurrentDispatcher 的接口
+type AsyncDispatcher[EventKind, EventValue, ListenerID comparable] struct {
+	*ConcurrentDispatcher[EventKind, EventValue, ListenerID]
+
+	options    AsyncOptions[EventKind, EventValue]      // 选项
+	mtx        sync.RWMutex                             // 保护状态及队列的关闭
+	state      int                                      // 状态
+	queues     []chan asyncEvent[EventKind, EventValue] // 事件队列，未分区时所有工作协程共享一个队列
+	wg         sync.WaitGroup                           // 等待工作协程退出
+	partMtx    sync.Mutex                               // 保护分区状态
+	partitions map[interface{}]*asyncPartition          // 有未派发完成事件的分区
+	nextWorker int                                      // 下一个新分区分配的工作协程
+}
+
+// opts 为底层 ConcurrentDispatcher 的选项，建议开启 WithRecoverPanic，避免监听者 panic 导致工作协程退出
+func NewAsyncDispatcher[EventKind, EventValue, ListenerID comparable](options AsyncOptions[EventKind, EventValue], opts ...DispatcherOption[EventKind, EventValue, ListenerID]) *AsyncDispatcher[EventKind, EventValue, ListenerID] {
+	if options.QueueSize <= 0 {
+		options.QueueSize = defaultAsyncQueueSize
+	}
+	if options.Workers <= 0 {
+		options.Workers = defaultAsyncWorkers
+	}
+	d := &AsyncDispatcher[EventKind, EventValue, ListenerID]{
+		ConcurrentDispatcher: NewConcurrentDispatcher(opts...),
+		options:              options,
+	}
+	queues := 1
+	if options.Partitioner != nil {
+		queues = options.Workers
+		d.partitions = map[interface{}]*asyncPartition{}
+	}
+	d.queues = make([]chan asyncEvent[EventKind, EventValue], queues)
+	for i := range d.queues {
+		d.queues[i] = make(chan asyncEvent[EventKind, EventValue], options.QueueSize)
+	}
+	return d
+}
+
+// Start 启动工作协程，开始派发队列中的事件
+func (d *AsyncDispatcher[EventKind, EventValue, ListenerID]) Start() error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	switch d.state {
+	case asyncStateRunning:
+		return ErrAsyncDispatcherStarted
+	case asyncStateStopped:
+		return ErrAsyncDispatcherStopped
+	}
+	d.state = asyncStateRunning
+	for i := 0; i < d.options.Workers; i++ {
+		d.wg.Add(1)
+		go d.work(d.queues[i%len(d.queues)])
+	}
+	return nil
+}
+
+// Stop 停止派发器
+// 停止后不再接收新的事件，工作协程会派发完队列中剩余的事件后退出
+// ctx 结束时不再等待，返回 ctx 的错误，剩余事件仍会在后台派发完毕
+// 未启动过的派发器停止时，队列中的事件会被丢弃
+func (d *AsyncDispatcher[EventKind, EventValue, ListenerID]) Stop(ctx context.Context) error {
+	d.mtx.Lock()
+	if d.state == asyncStateStopped {
+		d.mtx.Unlock()
+		return ErrAsyncDispatcherStopped
+	}
+	d.state = asyncStateStopped
+	for _, queue := range d.queues {
+		close(queue)
+	}
+	d.mtx.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Post 构造事件，投递到队列等待异步派发
+// 队列已满时返回 ErrAsyncQueueFull，派发器停止后返回 ErrAsyncDispatcherStopped
+func (d *AsyncDispatcher[EventKind, EventValue, ListenerID]) Post(evtId EventID[EventKind, EventValue], generator interface{}, param ...interface{}) error {
+	evt := asyncEvent[EventKind, EventValue]{
+		evtId:     evtId,
+		generator: generator,
+	}
+	if len(param) > 0 {
+		evt.param = param[0]
+	}
+
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+	if d.state == asyncStateStopped {
+		return ErrAsyncDispatcherStopped
+	}
+
+	queue := d.queues[0]
+	if d.options.Partitioner != nil {
+		evt.partition = d.acquirePartition(d.options.Partitioner(evtId))
+		queue = d.queues[evt.partition.worker]
+	}
+	select {
+	case queue <- evt:
+		return nil
+	default:
+		if evt.partition != nil {
+			d.releasePartition(evt.partition)
+		}
+		return ErrAsyncQueueFull
+	}
+}
+
+// acquirePartition 获取分区，并增加分区内未派发完成的事件数量
+// 新的分区按照轮询的方式分配给工作协程
+func (d *AsyncDispatcher[EventKind, EventValue, ListenerID]) acquirePartition(key interface{}) *asyncPartition {
+	d.partMtx.Lock()
+	defer d.partMtx.Unlock()
+	p := d.partitions[key]
+	if p == nil {
+		p = &asyncPartition{key: key, worker: d.nextWorker}
+		d.nextWorker = (d.nextWorker + 1) % len(d.queues)
+		d.partitions[key] = p
+	}
+	p.pending++
+	return p
+}
+
+// releasePartition 减少分区内未派发完成的事件数量，没有未派发完成的事件时移除分区
+func (d *AsyncDispatcher[EventKind, EventValue, ListenerID]) releasePartition(p *asyncPartition) {
+	d.partMtx.Lock()
+	defer d.partMtx.Unlock()
+	p.pending--
+	if p.pending <= 0 {
+		delete(d.partitions, p.key)
+	}
+}
+
+// work 工作协程，持续派发队列中的事件，直到队列关闭
+func (d *AsyncDispatcher[EventKind, EventValue, ListenerID]) work(queue chan asyncEvent[EventKind, EventValue]) {
+	defer d.wg.Done()
+	for evt := range queue {
+		err := d.Dispatch(evt.evtId, evt.generator, evt.param)
+		if evt.partition != nil {
+			d.releasePartition(evt.partition)
+		}
+		if err != nil && d.options.ErrorHandler != nil {
+			d.options.ErrorHandler(evt.evtId, err)
+		}
+	}
+}
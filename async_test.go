@@ -0,0 +1,149 @@
+package gevent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAsyncDispatcher(t *testing.T) {
+	testErr := errors.New("test error")
+	var errCount int64
+	dispatcher := NewAsyncDispatcher[testET, testEV, testLID](AsyncOptions[testET, testEV]{
+		QueueSize: 128,
+		Workers:   4,
+		ErrorHandler: func(evtId testEventID, err error) {
+			if !errors.Is(err, testErr) {
+				t.Error("error must be test error")
+			}
+			atomic.AddInt64(&errCount, 1)
+		},
+	})
+
+	eventType := testET(1)
+	var value int64
+	dispatcher.AddKindListener(eventType, 1, func(e testEvent) error {
+		atomic.AddInt64(&value, int64(e.Param().(int)))
+		if e.EventID().Value == 0 {
+			return testErr
+		}
+		return nil
+	})
+
+	// 启动前投递的事件在启动后派发
+	if err := dispatcher.Post(testEventID{eventType, 0}, nil, 1); err != nil {
+		t.Fatal("post:", err)
+	}
+	if err := dispatcher.Start(); err != nil {
+		t.Fatal("start:", err)
+	}
+	if err := dispatcher.Start(); err != ErrAsyncDispatcherStarted {
+		t.Fatal("start twice must fail")
+	}
+	for i := 0; i < 99; i++ {
+		if err := dispatcher.Post(testEventID{eventType, 1}, nil, 1); err != nil {
+			t.Fatal("post:", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := dispatcher.Stop(ctx); err != nil {
+		t.Fatal("stop:", err)
+	}
+	if value != 100 {
+		t.Fatal("value must be", 100, "got", value)
+	}
+	if errCount != 1 {
+		t.Fatal("error count must be", 1, "got", errCount)
+	}
+	if err := dispatcher.Post(testEventID{eventType, 1}, nil, 1); err != ErrAsyncDispatcherStopped {
+		t.Fatal("post after stop must fail")
+	}
+}
+
+func TestAsyncDispatcherQueueFull(t *testing.T) {
+	dispatcher := NewAsyncDispatcher[testET, testEV, testLID](AsyncOptions[testET, testEV]{QueueSize: 1})
+	if err := dispatcher.Post(testEventID{1, 1}, nil); err != nil {
+		t.Fatal("post:", err)
+	}
+	if err := dispatcher.Post(testEventID{1, 1}, nil); err != ErrAsyncQueueFull {
+		t.Fatal("queue must be full")
+	}
+	if err := dispatcher.Stop(context.Background()); err != nil {
+		t.Fatal("stop:", err)
+	}
+}
+
+func TestAsyncDispatcherStopTimeout(t *testing.T) {
+	dispatcher := NewAsyncDispatcher[testET, testEV, testLID](AsyncOptions[testET, testEV]{})
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		defer wg.Done()
+		<-release
+		return nil
+	})
+	dispatcher.Start()
+	dispatcher.Post(testEventID{1, 1}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := dispatcher.Stop(ctx); err != context.DeadlineExceeded {
+		t.Fatal("stop must time out, got", err)
+	}
+	close(release)
+	wg.Wait()
+}
+
+func TestAsyncDispatcherPartition(t *testing.T) {
+	maxEventType := 8
+	count := 200
+	dispatcher := NewAsyncDispatcher[testET, testEV, testLID](AsyncOptions[testET, testEV]{
+		QueueSize:   maxEventType * count,
+		Workers:     4,
+		Partitioner: PartitionByKind[testET, testEV](),
+	})
+
+	var mtx sync.Mutex
+	received := map[testET][]int{}
+	for i := 0; i < maxEventType; i++ {
+		dispatcher.AddKindListener(testET(i), 1, func(e testEvent) error {
+			mtx.Lock()
+			received[e.EventID().Kind] = append(received[e.EventID().Kind], e.Param().(int))
+			mtx.Unlock()
+			return nil
+		})
+	}
+
+	dispatcher.Start()
+	for i := 0; i < count; i++ {
+		for k := 0; k < maxEventType; k++ {
+			if err := dispatcher.Post(testEventID{testET(k), testEV(i)}, nil, i); err != nil {
+				t.Fatal("post:", err)
+			}
+		}
+	}
+	if err := dispatcher.Stop(context.Background()); err != nil {
+		t.Fatal("stop:", err)
+	}
+
+	for k := 0; k < maxEventType; k++ {
+		values := received[testET(k)]
+		if len(values) != count {
+			t.Fatal("kind", k, "must receive", count, "events, got", len(values))
+		}
+		for i, v := range values {
+			if v != i {
+				t.Fatal("kind", k, "events out of order")
+			}
+		}
+	}
+	if len(dispatcher.partitions) != 0 {
+		t.Fatal("partitions must clear")
+	}
+}
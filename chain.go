@@ -0,0 +1,93 @@
+package gevent
+
+import "errors"
+
+// ErrDispatcherCycle 派发链成环
+// 将派发器自身或其后代设置为父派发器时返回
+var ErrDispatcherCycle = errors.New("dispatcher cycle")
+
+// SetParent 设置父派发器，将派发器链接到派发链中，parent 为空时解除链接
+// 事件在派发链中的传递类似 DOM 事件：
+// 1. 捕获阶段，自根派发器向下直到本派发器，依次派发给各派发器的捕获监听者；
+// 2. 目标阶段，派发给本派发器的监听者；
+// 3. 冒泡阶段，自父派发器向上直到根派发器，依次派发给各派发器的监听者。
+// 监听者返回 ErrStopPropagation 时事件被取消，不再传递；返回 ErrStopBubbling 时当前派发器的当前阶段派发完成后不再传递
+// 整条派发链使用发起派发的派发器的选项，如错误处理策略等
+// parent 为派发器自身或其后代时返回 ErrDispatcherCycle
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) SetParent(parent *Dispatcher[EventKind, EventValue, ListenerID]) error {
+	for p := parent; p != nil; p = p.parent {
+		if p == d {
+			return ErrDispatcherCycle
+		}
+	}
+	d.parent = parent
+	return nil
+}
+
+// Parent 返回父派发器
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) Parent() *Dispatcher[EventKind, EventValue, ListenerID] {
+	return d.parent
+}
+
+// AddCaptureListener 添加捕获监听者，在捕获阶段接收 evtKind 类型的事件
+// 后代派发器发起的事件，先于后代派发器的监听者被捕获监听者接收
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) AddCaptureListener(evtKind EventKind, lID ListenerID, callback ListenerCallback[EventKind, EventValue], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return d.addListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeCapture,
+		EventID:    EventID[EventKind, EventValue]{Kind: evtKind},
+		ListenerID: lID,
+	}, callback, nil, newListenerOptions(opts))
+}
+
+// RemCaptureListener 移除捕获监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) RemCaptureListener(evtKind EventKind, lID ListenerID) bool {
+	return d.remListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeCapture,
+		EventID:    EventID[EventKind, EventValue]{Kind: evtKind},
+		ListenerID: lID,
+	})
+}
+
+// remCaptureListener 移除捕获监听者，容器中已没有监听者时将其移除
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) remCaptureListener(evtKind EventKind, lID ListenerID) bool {
+	lc := d.captureListeners[evtKind]
+	if lc == nil {
+		return false
+	}
+	rem := lc.remListener(lID)
+	if lc.noListener() {
+		delete(d.captureListeners, evtKind)
+		if len(d.captureListeners) == 0 {
+			d.captureListeners = nil
+		}
+	}
+	return rem
+}
+
+// propagationPath 返回事件的传递路径，依次为本派发器及各级祖先派发器
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) propagationPath() []*Dispatcher[EventKind, EventValue, ListenerID] {
+	var path []*Dispatcher[EventKind, EventValue, ListenerID]
+	for p := d; p != nil; p = p.parent {
+		path = append(path, p)
+	}
+	return path
+}
+
+// dispatchCapture 将事件派发给本派发器的捕获监听者们
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) dispatchCapture(evt Event[EventKind, EventValue], opts *dispatcherOptions[EventKind, EventValue, ListenerID], errs []*DispatchError[EventKind, EventValue, ListenerID]) (dispatchState, []*DispatchError[EventKind, EventValue, ListenerID]) {
+	lc := d.captureListeners[evt.eventID.Kind]
+	if lc == nil {
+		return dispatchContinue, errs
+	}
+	d.dispatching++
+	defer d.endDispatch()
+
+	state, errs := lc.dispatch(evt, DispatchStageCapture, opts, errs)
+	if lc.noListener() {
+		delete(d.captureListeners, evt.eventID.Kind)
+		if len(d.captureListeners) == 0 {
+			d.captureListeners = nil
+		}
+	}
+	return state, errs
+}
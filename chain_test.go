@@ -0,0 +1,160 @@
+package gevent
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDispatcherChain(t *testing.T) {
+	world := NewDispatcher[testET, testEV, testLID]()
+	room := NewDispatcher[testET, testEV, testLID]()
+	entity := NewDispatcher[testET, testEV, testLID]()
+	if room.SetParent(world) != nil || entity.SetParent(room) != nil {
+		t.Fatal("set parent must succeed")
+	}
+	if entity.Parent() != room || room.Parent() != world || world.Parent() != nil {
+		t.Fatal("parent mismatch")
+	}
+
+	var order []string
+	record := func(name string) testListenerCallback {
+		return func(e testEvent) error {
+			order = append(order, fmt.Sprintf("%s:%v", name, e.Phase()))
+			return nil
+		}
+	}
+	world.AddKindListener(1, 1, record("world"))
+	world.AddCaptureListener(1, 1, record("world"))
+	room.AddKindListener(1, 1, record("room"))
+	room.AddCaptureListener(1, 1, record("room"))
+	entity.AddKindListener(1, 1, record("entity"))
+	entity.AddCaptureListener(1, 1, record("entity"))
+
+	expected := "[world:capture room:capture entity:capture entity:target room:bubble world:bubble]"
+	entity.Dispatch(testEventID{1, 1}, nil)
+	if fmt.Sprint(order) != expected {
+		t.Fatal("order must be", expected, "got", order)
+	}
+
+	// 从中间的派发器发起时，只经过其祖先
+	order = nil
+	expected = "[world:capture room:capture room:target world:bubble]"
+	room.Dispatch(testEventID{1, 1}, nil)
+	if fmt.Sprint(order) != expected {
+		t.Fatal("order must be", expected, "got", order)
+	}
+
+	// 停止冒泡：当前派发器的后续监听者仍然接收事件，但不再传递给祖先
+	order = nil
+	room.AddKindListener(1, 2, func(e testEvent) error {
+		return ErrStopBubbling
+	}, WithPriority(1))
+	expected = "[world:capture room:capture entity:capture entity:target room:bubble]"
+	if cancelled, err := entity.DispatchCancelable(testEventID{1, 1}, nil); cancelled || err != nil {
+		t.Fatal("event must not be cancelled")
+	}
+	if fmt.Sprint(order) != expected {
+		t.Fatal("order must be", expected, "got", order)
+	}
+	room.RemKindListener(1, 2)
+
+	// 捕获阶段停止传播，事件被取消
+	order = nil
+	world.AddCaptureListener(1, 2, func(e testEvent) error {
+		return ErrStopPropagation
+	})
+	expected = "[world:capture]"
+	if cancelled, _ := entity.DispatchCancelable(testEventID{1, 1}, nil); !cancelled {
+		t.Fatal("event must be cancelled")
+	}
+	if fmt.Sprint(order) != expected {
+		t.Fatal("order must be", expected, "got", order)
+	}
+	world.RemCaptureListener(1, 2)
+
+	// 祖先派发器的错误同样返回给发起派发的派发器
+	testErr := errors.New("test error")
+	world.AddCaptureListener(2, 1, func(e testEvent) error {
+		return testErr
+	})
+	err := entity.Dispatch(testEventID{2, 1}, nil)
+	var dispatchErrs *DispatchErrors[testET, testEV, testLID]
+	if !errors.As(err, &dispatchErrs) || dispatchErrs.Len() != 1 || dispatchErrs.Errors()[0].Stage != DispatchStageCapture {
+		t.Fatal("error must be from capture stage")
+	}
+
+	// 解除链接后不再传递
+	order = nil
+	entity.SetParent(nil)
+	entity.Dispatch(testEventID{1, 1}, nil)
+	expected = "[entity:capture entity:target]"
+	if fmt.Sprint(order) != expected {
+		t.Fatal("order must be", expected, "got", order)
+	}
+}
+
+func TestDispatcherCycle(t *testing.T) {
+	a := NewDispatcher[testET, testEV, testLID]()
+	b := NewDispatcher[testET, testEV, testLID]()
+	c := NewDispatcher[testET, testEV, testLID]()
+	if a.SetParent(a) != ErrDispatcherCycle {
+		t.Fatal("self parent must be rejected")
+	}
+	b.SetParent(a)
+	c.SetParent(b)
+	if a.SetParent(c) != ErrDispatcherCycle {
+		t.Fatal("descendant parent must be rejected")
+	}
+	if a.Parent() != nil {
+		t.Fatal("parent must not be changed")
+	}
+}
+
+func TestDispatcherChainOnDispatching(t *testing.T) {
+	parent := NewDispatcher[testET, testEV, testLID]()
+	child := NewDispatcher[testET, testEV, testLID]()
+	child.SetParent(parent)
+	value := 0
+
+	add := func(e testEvent) error {
+		value += 1
+		return nil
+	}
+	// 整个派发过程中添加的监听者都被挂起，在派发完成后才添加，不会接收到本次事件
+	parent.AddCaptureListener(1, 1, func(e testEvent) error {
+		if _, ok := child.AddKindListener(1, 1, add); !ok {
+			t.Fatal("add listener must succeed")
+		}
+		return ErrRemAfterDispatch
+	})
+	child.AddCaptureListener(1, 1, func(e testEvent) error {
+		if _, ok := child.AddKindListener(1, 2, add); !ok {
+			t.Fatal("add listener must succeed")
+		}
+		return ErrRemAfterDispatch
+	})
+	child.Dispatch(testEventID{1, 1}, nil)
+	if value != 0 {
+		t.Fatal("value must be", 0)
+	}
+	if parent.captureListeners != nil || child.captureListeners != nil {
+		t.Fatal("capture listeners must be removed")
+	}
+	child.Dispatch(testEventID{1, 1}, nil)
+	if value != 2 {
+		t.Fatal("value must be", 2)
+	}
+
+	// 没有父派发器时，捕获阶段添加的监听者同样不会在目标阶段接收到本次事件
+	single := NewDispatcher[testET, testEV, testLID]()
+	value = 0
+	single.AddCaptureListener(1, 1, func(e testEvent) error {
+		single.AddKindListener(1, 1, add)
+		return ErrRemAfterDispatch
+	})
+	single.Dispatch(testEventID{1, 1}, nil)
+	if value != 0 || len(single.ListenersOf(1)) != 1 {
+		t.Fatal("value must be", 0)
+	}
+}
@@ -0,0 +1,154 @@
+package gevent
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// errEventDropped 事件被丢弃
+// channel 订阅的监听者回调专用，表示 channel 已满，事件被丢弃，不计入接收次数
+var errEventDropped = errors.New("event dropped")
+
+// OverflowPolicy channel 订阅的溢出策略，决定 channel 已满时如何处理新的事件
+type OverflowPolicy int
+
+const (
+	OverflowBlock      OverflowPolicy = iota // 阻塞派发，直到 channel 有空间；回放粘性事件时不阻塞，按照 OverflowDropOldest 处理
+	OverflowDropNewest                       // 丢弃新的事件，被丢弃的事件不计入接收次数
+	OverflowDropOldest                       // 丢弃 channel 中最早的事件，写入新的事件
+)
+
+// SubscribeKind 以 channel 的方式订阅事件类型，返回接收事件的 channel 以及取消订阅的函数
+// 参见 subscribeChan
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) SubscribeKind(evtKind EventKind, lID ListenerID, bufSize int, overflow OverflowPolicy, opts ...ListenerOption) (<-chan Event[EventKind, EventValue], func(), bool) {
+	return d.subscribeChan(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeKind,
+		EventID:    EventID[EventKind, EventValue]{Kind: evtKind},
+		ListenerID: lID,
+	}, bufSize, overflow, newListenerOptions(opts))
+}
+
+// SubscribeValue 以 channel 的方式订阅事件ID，返回接收事件的 channel 以及取消订阅的函数
+// 参见 subscribeChan
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) SubscribeValue(evtId EventID[EventKind, EventValue], lID ListenerID, bufSize int, overflow OverflowPolicy, opts ...ListenerOption) (<-chan Event[EventKind, EventValue], func(), bool) {
+	return d.subscribeChan(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeValue,
+		EventID:    evtId,
+		ListenerID: lID,
+	}, bufSize, overflow, newListenerOptions(opts))
+}
+
+// subscribeChan 添加将事件写入容量为 bufSize 的 channel 的监听者
+// channel 已满时按照 overflow 处理；监听者被移除时关闭 channel，包括取消订阅、接收次数耗尽、过期以及 Clear 等
+// 被 OverflowDropNewest 丢弃的事件不计入 WithOnce、WithTimes 的接收次数
+// 添加失败时返回 false；channel 的写入在派发器所在的 goroutine 中进行
+// 取消订阅的函数可以在任意 goroutine 中调用，调用后立即关闭 channel，并唤醒因 OverflowBlock 阻塞的派发，
+// 监听者随即失效，由派发器在之后的派发、Tick 或添加与上下文绑定的监听者时移除
+// 订阅时回放的粘性事件写入 channel 时不会阻塞，超出 bufSize 的部分按照 OverflowDropOldest 丢弃，仅保留最新的事件
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) subscribeChan(reg Registration[EventKind, EventValue, ListenerID], bufSize int, overflow OverflowPolicy, opts listenerOptions) (<-chan Event[EventKind, EventValue], func(), bool) {
+	if bufSize < 0 {
+		panic("subscription buffer size must not be negative")
+	}
+	parent := opts.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	// 取消订阅时结束监听者的上下文，使其在派发器所在的 goroutine 中被视为过期
+	ctx, cancelCtx := context.WithCancel(parent)
+	opts.ctx = ctx
+	cs := newChanSubscription[EventKind, EventValue](bufSize, overflow)
+	l := newListener(reg, cs.send, nil, opts, d.options.clock)
+	l.onReset = func() {
+		cancelCtx()
+		cs.close()
+	}
+	if _, ok := d.subscribe(l); !ok {
+		cancelCtx()
+		return nil, nil, false
+	}
+	return cs.ch, func() {
+		cancelCtx()
+		cs.close()
+	}, true
+}
+
+// chanSubscription channel 订阅
+// 写入与关闭 channel 由互斥锁保护，因此可以在任意 goroutine 中关闭
+type chanSubscription[EventKind, EventValue comparable] struct {
+	ch       chan Event[EventKind, EventValue] // 接收事件的 channel
+	overflow OverflowPolicy                    // 溢出策略
+	done     chan struct{}                     // 关闭时先关闭，用于唤醒阻塞的写入
+	mtx      sync.Mutex                        // 保护 channel 的写入与关闭
+	closed   bool                              // channel 是否已关闭
+	once     sync.Once                         // 保证只关闭一次
+}
+
+func newChanSubscription[EventKind, EventValue comparable](bufSize int, overflow OverflowPolicy) *chanSubscription[EventKind, EventValue] {
+	return &chanSubscription[EventKind, EventValue]{
+		ch:       make(chan Event[EventKind, EventValue], bufSize),
+		overflow: overflow,
+		done:     make(chan struct{}),
+	}
+}
+
+// close 关闭 channel，可以在任意 goroutine 中调用，重复调用无影响
+func (cs *chanSubscription[EventKind, EventValue]) close() {
+	cs.once.Do(func() {
+		close(cs.done)
+		cs.mtx.Lock()
+		cs.closed = true
+		close(cs.ch)
+		cs.mtx.Unlock()
+	})
+}
+
+// send 监听者回调，按照溢出策略将事件写入 channel
+// channel 已关闭时返回 ErrRemAfterDispatch，由派发器移除监听者
+func (cs *chanSubscription[EventKind, EventValue]) send(evt Event[EventKind, EventValue]) error {
+	cs.mtx.Lock()
+	defer cs.mtx.Unlock()
+	if cs.closed {
+		return ErrRemAfterDispatch
+	}
+	evt.stopped = nil
+	policy := cs.overflow
+	if policy == OverflowBlock && evt.replayed {
+		// 回放发生在添加监听者的调用中，此时调用方尚未开始读取 channel
+		policy = OverflowDropOldest
+	}
+	switch policy {
+	case OverflowDropNewest:
+		select {
+		case cs.ch <- evt:
+			return nil
+		default:
+			return errEventDropped
+		}
+	case OverflowDropOldest:
+		select {
+		case cs.ch <- evt:
+			return nil
+		default:
+		}
+		select {
+		case <-cs.ch:
+		default:
+		}
+		select {
+		case cs.ch <- evt:
+			return nil
+		default:
+			// 容量为 0 且没有 goroutine 等待读取
+			return errEventDropped
+		}
+	default:
+		select {
+		case cs.ch <- evt:
+			return nil
+		case <-cs.done:
+			// 阻塞期间被取消订阅
+			return ErrRemAfterDispatch
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package gevent
+
+import (
+	"testing"
+	"time"
+)
+
+// drainChan 读取 channel 中已有的事件值，channel 已关闭时 closed 为 true
+func drainChan(ch <-chan testEvent) (values []testEV, closed bool) {
+	for {
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				return values, true
+			}
+			values = append(values, e.EventID().Value)
+		default:
+			return values, false
+		}
+	}
+}
+
+func TestChanSubscription(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+
+	kindCh, cancelKind, ok := dispatcher.SubscribeKind(1, 1, 4, OverflowBlock)
+	if !ok {
+		t.Fatal("subscribe must succeed")
+	}
+	valueCh, cancelValue, _ := dispatcher.SubscribeValue(testEventID{1, 2}, 2, 4, OverflowBlock)
+	if _, _, ok := dispatcher.SubscribeKind(1, 1, 4, OverflowBlock); ok {
+		t.Fatal("subscribe with duplicate id must fail")
+	}
+
+	for i := 1; i <= 3; i++ {
+		dispatcher.Dispatch(testEventID{1, testEV(i)}, nil)
+	}
+	if values, closed := drainChan(kindCh); len(values) != 3 || closed {
+		t.Fatal("values must be [1 2 3], got", values)
+	}
+	if values, _ := drainChan(valueCh); len(values) != 1 || values[0] != 2 {
+		t.Fatal("values must be [2], got", values)
+	}
+
+	// 取消订阅后关闭 channel，重复取消无影响
+	cancelKind()
+	cancelKind()
+	if _, closed := drainChan(kindCh); !closed {
+		t.Fatal("channel must be closed")
+	}
+	dispatcher.RemListener(2)
+	if _, closed := drainChan(valueCh); !closed {
+		t.Fatal("channel must be closed")
+	}
+	cancelValue()
+
+	// Clear 同样关闭 channel
+	ch, _, _ := dispatcher.SubscribeKind(1, 3, 0, OverflowDropNewest)
+	dispatcher.Clear()
+	if _, closed := drainChan(ch); !closed {
+		t.Fatal("channel must be closed")
+	}
+}
+
+func TestChanSubscriptionOverflow(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+
+	newestCh, _, _ := dispatcher.SubscribeKind(1, 1, 2, OverflowDropNewest)
+	oldestCh, _, _ := dispatcher.SubscribeKind(1, 2, 2, OverflowDropOldest)
+	for i := 1; i <= 4; i++ {
+		dispatcher.Dispatch(testEventID{1, testEV(i)}, nil)
+	}
+	if values, _ := drainChan(newestCh); len(values) != 2 || values[0] != 1 || values[1] != 2 {
+		t.Fatal("values must be [1 2], got", values)
+	}
+	if values, _ := drainChan(oldestCh); len(values) != 2 || values[0] != 3 || values[1] != 4 {
+		t.Fatal("values must be [3 4], got", values)
+	}
+
+	// 被丢弃的事件不计入接收次数
+	onceCh, _, _ := dispatcher.SubscribeKind(2, 3, 0, OverflowDropNewest, WithOnce())
+	dispatcher.Dispatch(testEventID{2, 1}, nil)
+	if values, closed := drainChan(onceCh); len(values) != 0 || closed {
+		t.Fatal("channel must not be closed")
+	}
+	received := make(chan testEV)
+	go func() {
+		for e := range onceCh {
+			received <- e.EventID().Value
+		}
+		close(received)
+	}()
+	for {
+		dispatcher.Dispatch(testEventID{2, 2}, nil)
+		if len(dispatcher.ListenersOf(3)) == 0 {
+			break
+		}
+	}
+	if <-received != 2 {
+		t.Fatal("value must be", 2)
+	}
+	if _, ok := <-received; ok {
+		t.Fatal("channel must be closed after once")
+	}
+}
+
+func TestChanSubscriptionBlock(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	dispatcher.DispatchSticky(testEventID{1, 1}, nil)
+
+	// 订阅时回放粘性事件
+	ch, cancel, _ := dispatcher.SubscribeKind(1, 1, 1, OverflowBlock, WithTimes(3))
+	done := make(chan []testEV)
+	go func() {
+		var values []testEV
+		for e := range ch {
+			values = append(values, e.EventID().Value)
+		}
+		done <- values
+	}()
+	for i := 2; i <= 4; i++ {
+		dispatcher.Dispatch(testEventID{1, testEV(i)}, nil)
+	}
+	values := <-done
+	if len(values) != 3 || values[0] != 1 || values[2] != 3 {
+		t.Fatal("values must be [1 2 3], got", values)
+	}
+	cancel()
+}
+
+func TestChanSubscriptionReplayOverflow(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	for i := 1; i <= 3; i++ {
+		dispatcher.DispatchSticky(testEventID{1, testEV(i)}, nil)
+	}
+
+	// 回放超出容量的粘性事件时不阻塞，仅保留最新的事件
+	done := make(chan struct{})
+	var ch <-chan testEvent
+	go func() {
+		ch, _, _ = dispatcher.SubscribeKind(1, 1, 1, OverflowBlock)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("subscribe must not block on replay")
+	}
+	values, _ := drainChan(ch)
+	if len(values) != 1 || values[0] != 3 {
+		t.Fatal("values must be [3], got", values)
+	}
+	if e, _ := dispatcher.StickyEvent(testEventID{1, 3}); e.Replayed() {
+		t.Fatal("sticky event must not be marked replayed")
+	}
+
+	// 回放之后的派发仍然阻塞
+	go func() {
+		dispatcher.Dispatch(testEventID{1, 4}, nil)
+		dispatcher.Dispatch(testEventID{1, 5}, nil)
+	}()
+	if e := <-ch; e.EventID().Value != 4 || e.Replayed() {
+		t.Fatal("value must be", 4)
+	}
+	if e := <-ch; e.EventID().Value != 5 {
+		t.Fatal("value must be", 5)
+	}
+}
+
+func TestChanSubscriptionCancel(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+
+	// 在读取 channel 的 goroutine 中取消订阅，唤醒阻塞的派发
+	ch, cancel, _ := dispatcher.SubscribeKind(1, 1, 0, OverflowBlock)
+	dispatched := make(chan struct{})
+	go func() {
+		dispatcher.Dispatch(testEventID{1, 1}, nil)
+		dispatcher.Dispatch(testEventID{1, 2}, nil)
+		close(dispatched)
+	}()
+	if e := <-ch; e.EventID().Value != 1 {
+		t.Fatal("value must be", 1)
+	}
+	// 不再读取第二个事件，直接取消订阅
+	cancel()
+	cancel()
+	select {
+	case <-dispatched:
+	case <-time.After(time.Second):
+		t.Fatal("dispatch must not block after cancel")
+	}
+	if _, ok := <-ch; ok {
+		t.Fatal("channel must be closed")
+	}
+	if dispatcher.kindListenerContainers[1] != nil || len(dispatcher.ListenersOf(1)) != 0 {
+		t.Fatal("listener must be removed")
+	}
+
+	// 已过期的订阅同样可以取消，并关闭 channel
+	clock := newTestClock()
+	dispatcher = NewDispatcher(WithClock[testET, testEV, testLID](clock))
+	ch, cancel, _ = dispatcher.SubscribeKind(1, 1, 1, OverflowBlock, WithTTL(time.Second))
+	clock.Advance(2 * time.Second)
+	cancel()
+	if _, closed := drainChan(ch); !closed {
+		t.Fatal("channel must be closed")
+	}
+	dispatcher.Tick()
+	if dispatcher.kindListenerContainers[1] != nil {
+		t.Fatal("listener must be removed")
+	}
+}
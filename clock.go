@@ -0,0 +1,17 @@
+package gevent
+
+import "time"
+
+// Clock 时钟
+// 派发器通过时钟获取当前时间，用于计算监听者的存活时间等，测试时可以注入可控的时钟
+type Clock interface {
+	// Now 返回当前时间
+	Now() time.Time
+}
+
+// systemClock 系统时钟
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,701 @@
+package gevent
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ConcurrentDispatcher 并发安全的事件派发器
+// 提供与 Dispatcher 相同的接口，可被任意多个 goroutine 同时调用
+// 监听者采用写时复制（copy-on-write）的方式维护：
+// 写操作在互斥锁保护下只复制受影响的监听者列表，修改后原子地发布；
+// 派发只读取快照，不需要加锁，监听者回调也不会在持有内部锁的情况下执行
+type ConcurrentDispatcher[EventKind, EventValue, ListenerID comparable] struct {
+	mtx                    sync.Mutex                                           // 写操作互斥锁
+	kindListenerContainers atomic.Value                                         // 按照事件类型划分的监听者容器，map[EventKind]*concurrentKindListenerContainer，不可变
+	options                dispatcherOptions[EventKind, EventValue, ListenerID] // 选项，创建后只读
+}
+
+func NewConcurrentDispatcher[EventKind, EventValue, ListenerID comparable](opts ...DispatcherOption[EventKind, EventValue, ListenerID]) *ConcurrentDispatcher[EventKind, EventValue, ListenerID] {
+	d := &ConcurrentDispatcher[EventKind, EventValue, ListenerID]{
+		options: newDispatcherOptions(opts),
+	}
+	d.kindListenerContainers.Store(map[EventKind]*concurrentKindListenerContainer[EventKind, EventValue, ListenerID]{})
+	return d
+}
+
+// AddKindListener 添加事件类型监听者
+// 添加成功时返回对应的订阅
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) AddKindListener(evtKind EventKind, lID ListenerID, callback ListenerCallback[EventKind, EventValue], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return d.addListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeKind,
+		EventID:    EventID[EventKind, EventValue]{Kind: evtKind},
+		ListenerID: lID,
+	}, callback, nil, newListenerOptions(opts))
+}
+
+// AddValueListener 添加值类型监听者
+// 添加成功时返回对应的订阅
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) AddValueListener(evtId EventID[EventKind, EventValue], lID ListenerID, callback ListenerCallback[EventKind, EventValue], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return d.addListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeValue,
+		EventID:    evtId,
+		ListenerID: lID,
+	}, callback, nil, newListenerOptions(opts))
+}
+
+// AddKindListenerContext 添加与 ctx 绑定的事件类型监听者
+// 派发器在后台监听 ctx，ctx 结束后立即移除监听者；ctx 已结束时不会添加监听者
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) AddKindListenerContext(ctx context.Context, evtKind EventKind, lID ListenerID, callback ListenerCallback[EventKind, EventValue], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	o, ok := newContextListenerOptions(ctx, opts)
+	if !ok {
+		return nil, false
+	}
+	return d.addListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeKind,
+		EventID:    EventID[EventKind, EventValue]{Kind: evtKind},
+		ListenerID: lID,
+	}, callback, nil, o)
+}
+
+// AddValueListenerContext 添加与 ctx 绑定的值类型监听者
+// 参见 AddKindListenerContext
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) AddValueListenerContext(ctx context.Context, evtId EventID[EventKind, EventValue], lID ListenerID, callback ListenerCallback[EventKind, EventValue], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	o, ok := newContextListenerOptions(ctx, opts)
+	if !ok {
+		return nil, false
+	}
+	return d.addListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeValue,
+		EventID:    evtId,
+		ListenerID: lID,
+	}, callback, nil, o)
+}
+
+// AddFilteredListener 添加带过滤器的事件类型监听者
+// 派发器在调用回调前以 filter 判断是否接收事件，被过滤掉的事件不计入接收次数
+// 监听者作为事件类型监听者添加，可通过 RemKindListener 移除
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) AddFilteredListener(evtKind EventKind, lID ListenerID, filter EventFilter[EventKind, EventValue], callback ListenerCallback[EventKind, EventValue], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	if filter == nil {
+		panic("listener filter nil")
+	}
+	return d.addListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeKind,
+		EventID:    EventID[EventKind, EventValue]{Kind: evtKind},
+		ListenerID: lID,
+	}, callback, filter, newListenerOptions(opts))
+}
+
+// RemKindListener 移除事件类型监听者
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) RemKindListener(evtKind EventKind, lID ListenerID) bool {
+	return d.remListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeKind,
+		EventID:    EventID[EventKind, EventValue]{Kind: evtKind},
+		ListenerID: lID,
+	}, nil)
+}
+
+// RemValueListener 移除值类型监听者
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) RemValueListener(evtId EventID[EventKind, EventValue], lID ListenerID) bool {
+	return d.remListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeValue,
+		EventID:    evtId,
+		ListenerID: lID,
+	}, nil)
+}
+
+// addListener 按照注册信息添加监听者
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) addListener(reg Registration[EventKind, EventValue, ListenerID], callback ListenerCallback[EventKind, EventValue], filter EventFilter[EventKind, EventValue], opts listenerOptions) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	if callback == nil {
+		panic("listener callback nil")
+	}
+	l := newConcurrentListener(reg.ListenerID, callback, filter, opts, d.options.clock)
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	klc := d.addORGetKindListeners(reg.EventID.Kind)
+	var add bool
+	if reg.Scope == ListenerScopeValue {
+		add = klc.addValueListener(reg.EventID.Value, l)
+	} else {
+		add = klc.kindListeners.addListener(l)
+	}
+	d.tidyKindListeners(reg.EventID.Kind, klc)
+	if !add {
+		return nil, false
+	}
+	if l.ctx != nil {
+		go d.watchContext(reg, l)
+	}
+	return newSubscription[EventKind, EventValue, ListenerID](d, l, reg), true
+}
+
+// watchContext 等待监听者所属的上下文结束后将其移除
+// 监听者先被移除时结束等待
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) watchContext(reg Registration[EventKind, EventValue, ListenerID], l *concurrentListener[EventKind, EventValue, ListenerID]) {
+	select {
+	case <-l.ctx.Done():
+		d.remListener(reg, l)
+	case <-l.stop:
+	}
+}
+
+// remListener 按照注册信息移除监听者
+// 若 target 不为空，仅当注册信息对应的监听者为 target 时移除
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) remListener(reg Registration[EventKind, EventValue, ListenerID], target *concurrentListener[EventKind, EventValue, ListenerID]) bool {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	klc := d.loadKindListenerContainers()[reg.EventID.Kind]
+	if klc == nil {
+		return false
+	}
+	var rem bool
+	if reg.Scope == ListenerScopeValue {
+		rem = klc.remValueListener(reg.EventID.Value, reg.ListenerID, target)
+	} else {
+		rem = klc.kindListeners.remListener(reg.ListenerID, target)
+	}
+	d.tidyKindListeners(reg.EventID.Kind, klc)
+	return rem
+}
+
+// unsubscribe 移除订阅对应的监听者
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) unsubscribe(s *Subscription[EventKind, EventValue, ListenerID]) bool {
+	return d.remListener(s.registration, s.handle.(*concurrentListener[EventKind, EventValue, ListenerID]))
+}
+
+// SetKindListenerPriority 修改事件类型监听者的优先级
+// 修改后监听者排在相同优先级监听者的末尾，对已开始的派发不生效
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) SetKindListenerPriority(evtKind EventKind, lID ListenerID, priority int) bool {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	klc := d.loadKindListenerContainers()[evtKind]
+	if klc == nil {
+		return false
+	}
+	return klc.kindListeners.setPriority(lID, priority)
+}
+
+// SetValueListenerPriority 修改值类型监听者的优先级
+// 修改后监听者排在相同优先级监听者的末尾，对已开始的派发不生效
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) SetValueListenerPriority(evtId EventID[EventKind, EventValue], lID ListenerID, priority int) bool {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	klc := d.loadKindListenerContainers()[evtId.Kind]
+	if klc == nil {
+		return false
+	}
+	lc := klc.valueListeners[evtId.Value]
+	if lc == nil {
+		return false
+	}
+	return lc.setPriority(lID, priority)
+}
+
+// Clear 清理状态，移除所有监听者
+// 正在进行的派发不会再将事件派发给已被移除的监听者
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) Clear() {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	for _, v := range d.loadKindListenerContainers() {
+		v.clear()
+	}
+	d.kindListenerContainers.Store(map[EventKind]*concurrentKindListenerContainer[EventKind, EventValue, ListenerID]{})
+}
+
+// Dispatch 构造事件，派发给 evtID 指定的监听者们
+// 派发过程只读取监听者快照，不加锁
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) Dispatch(evtId EventID[EventKind, EventValue], generator interface{}, param ...interface{}) error {
+	_, err := d.DispatchCancelable(evtId, generator, param...)
+	return err
+}
+
+// DispatchCancelable 构造事件，派发给 evtID 指定的监听者们
+// 返回事件是否被监听者通过 ErrStopPropagation 取消
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) DispatchCancelable(evtId EventID[EventKind, EventValue], generator interface{}, param ...interface{}) (bool, error) {
+	return d.dispatch(evtId, generator, param, &d.options)
+}
+
+// DispatchWithPolicy 构造事件，派发给 evtID 指定的监听者们
+// 本次派发使用 policy 指定的错误处理策略，返回事件是否被取消，参见 Dispatcher.DispatchWithPolicy
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) DispatchWithPolicy(policy ErrorPolicy, evtId EventID[EventKind, EventValue], generator interface{}, param ...interface{}) (bool, error) {
+	opts := d.options
+	opts.errorPolicy = policy
+	return d.dispatch(evtId, generator, param, &opts)
+}
+
+// dispatch 构造事件，按照 opts 先后向类型事件和值类事件的监听者快照派发事件
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) dispatch(evtId EventID[EventKind, EventValue], generator interface{}, param []interface{}, opts *dispatcherOptions[EventKind, EventValue, ListenerID]) (bool, error) {
+	klc := d.loadKindListenerContainers()[evtId.Kind]
+	if klc == nil {
+		return false, nil
+	}
+	kindListeners := klc.kindListeners.load()
+	valueListeners := klc.loadValueListeners(evtId.Value)
+	if len(kindListeners) == 0 && len(valueListeners) == 0 {
+		return false, nil
+	}
+
+	evt := Event[EventKind, EventValue]{
+		eventID:   evtId,
+		generator: generator,
+	}
+	if len(param) > 0 {
+		evt.param = param[0]
+	}
+
+	kindRems, state, errs := dispatchConcurrentListeners(kindListeners, evt, DispatchStageKind, opts, nil)
+	var valueRems []*concurrentListener[EventKind, EventValue, ListenerID]
+	if state == dispatchContinue {
+		valueRems, state, errs = dispatchConcurrentListeners(valueListeners, evt, DispatchStageValue, opts, errs)
+	}
+
+	if len(kindRems) > 0 || len(valueRems) > 0 {
+		d.removeDispatched(evtId, kindRems, valueRems)
+	}
+
+	return state == dispatchStopped, newDispatchErrors(errs)
+}
+
+// removeDispatched 移除派发过程中标记为移除的监听者
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) removeDispatched(evtId EventID[EventKind, EventValue], kindRems, valueRems []*concurrentListener[EventKind, EventValue, ListenerID]) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	klc := d.loadKindListenerContainers()[evtId.Kind]
+	if klc == nil {
+		return
+	}
+	for _, l := range kindRems {
+		klc.kindListeners.remListener(l.id, l)
+	}
+	for _, l := range valueRems {
+		klc.remValueListener(evtId.Value, l.id, l)
+	}
+	d.tidyKindListeners(evtId.Kind, klc)
+}
+
+// loadKindListenerContainers 加载当前发布的类型监听者容器 Map，返回值不可修改
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) loadKindListenerContainers() map[EventKind]*concurrentKindListenerContainer[EventKind, EventValue, ListenerID] {
+	return d.kindListenerContainers.Load().(map[EventKind]*concurrentKindListenerContainer[EventKind, EventValue, ListenerID])
+}
+
+// storeKindListenerContainers 复制当前的类型监听者容器 Map，经 modify 修改后发布
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) storeKindListenerContainers(modify func(map[EventKind]*concurrentKindListenerContainer[EventKind, EventValue, ListenerID])) {
+	old := d.loadKindListenerContainers()
+	m := make(map[EventKind]*concurrentKindListenerContainer[EventKind, EventValue, ListenerID], len(old)+1)
+	for k, v := range old {
+		m[k] = v
+	}
+	modify(m)
+	d.kindListenerContainers.Store(m)
+}
+
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) addORGetKindListeners(evtKind EventKind) *concurrentKindListenerContainer[EventKind, EventValue, ListenerID] {
+	klc := d.loadKindListenerContainers()[evtKind]
+	if klc == nil {
+		klc = newConcurrentKindListenerContainer[EventKind, EventValue, ListenerID]()
+		d.storeKindListenerContainers(func(m map[EventKind]*concurrentKindListenerContainer[EventKind, EventValue, ListenerID]) {
+			m[evtKind] = klc
+		})
+	}
+	return klc
+}
+
+// tidyKindListeners 若类型容器中已没有监听者，将其移除
+func (d *ConcurrentDispatcher[EventKind, EventValue, ListenerID]) tidyKindListeners(evtKind EventKind, klc *concurrentKindListenerContainer[EventKind, EventValue, ListenerID]) {
+	if klc.noListener() {
+		d.storeKindListenerContainers(func(m map[EventKind]*concurrentKindListenerContainer[EventKind, EventValue, ListenerID]) {
+			delete(m, evtKind)
+		})
+	}
+}
+
+// dispatchConcurrentListeners 向监听者快照派发事件
+// 监听者们产生的错误按 stage 封装后，依照错误处理策略追加到 errs
+// 返回需要在派发后移除的监听者，派发状态，以及追加后的 errs
+func dispatchConcurrentListeners[EventKind, EventValue, ListenerID comparable](listeners []*concurrentListener[EventKind, EventValue, ListenerID], evt Event[EventKind, EventValue], stage DispatchStage, opts *dispatcherOptions[EventKind, EventValue, ListenerID], errs []*DispatchError[EventKind, EventValue, ListenerID]) ([]*concurrentListener[EventKind, EventValue, ListenerID], dispatchState, []*DispatchError[EventKind, EventValue, ListenerID]) {
+	var rems []*concurrentListener[EventKind, EventValue, ListenerID]
+	state := dispatchContinue
+	for _, l := range listeners {
+		rem, err := l.dispatch(evt, opts)
+		if err == ErrStopPropagation {
+			state = dispatchStopped
+		} else if isListenerError(err) {
+			errs, state = opts.onError(errs, &DispatchError[EventKind, EventValue, ListenerID]{
+				EventID:    evt.eventID,
+				Stage:      stage,
+				ListenerID: l.id,
+				Err:        err,
+			})
+		}
+		if rem {
+			rems = append(rems, l)
+		}
+		if state != dispatchContinue {
+			break
+		}
+	}
+	return rems, state, errs
+}
+
+// concurrentListener 并发派发器的监听者
+// 移除状态及剩余次数通过原子操作维护，保证并发派发时监听者接收事件的次数不超出限制
+type concurrentListener[EventKind, EventValue, ListenerID comparable] struct {
+	delivered   uint64                                  // 接收事件的次数，原子访问，置于开头以保证 64 位对齐
+	filteredOut uint64                                  // 被过滤掉的事件数，原子访问
+	errors      uint64                                  // 产生错误的次数，原子访问
+	id          ListenerID                              // 监听者ID
+	callback    ListenerCallback[EventKind, EventValue] // 监听者回调
+	times       int32                                   // 剩余可接收事件的次数，小于 0 表示不限，原子访问
+	deadline    time.Time                               // 过期时间，零值表示不会过期
+	clock       Clock                                   // 时钟，用于判断是否过期
+	ctx         context.Context                         // 所属的上下文，结束后监听者失效
+	filter      EventFilter[EventKind, EventValue]      // 过滤器，为空表示接收所有事件
+	priority    int                                     // 优先级，仅在持有派发器锁时访问
+	removed     int32                                   // 是否已被移除，原子访问
+	stop        chan struct{}                           // 被移除时关闭，用于结束对上下文的监听，未绑定上下文时为空
+}
+
+func newConcurrentListener[EventKind, EventValue, ListenerID comparable](id ListenerID, callback ListenerCallback[EventKind, EventValue], filter EventFilter[EventKind, EventValue], opts listenerOptions, clock Clock) *concurrentListener[EventKind, EventValue, ListenerID] {
+	if callback == nil {
+		panic("callback nil")
+	}
+	if opts.pacing != pacingNone {
+		panic("pacing listener options not supported by ConcurrentDispatcher")
+	}
+	l := &concurrentListener[EventKind, EventValue, ListenerID]{
+		id:       id,
+		callback: callback,
+		times:    -1,
+		deadline: opts.expireAt(clock),
+		clock:    clock,
+		ctx:      opts.ctx,
+		filter:   filter,
+		priority: opts.priority,
+	}
+	if opts.times > 0 {
+		l.times = int32(opts.times)
+	}
+	if opts.ctx != nil {
+		l.stop = make(chan struct{})
+	}
+	return l
+}
+
+// isRemoved 返回是否已被移除
+func (l *concurrentListener[EventKind, EventValue, ListenerID]) isRemoved() bool {
+	return atomic.LoadInt32(&l.removed) != 0
+}
+
+// expired 返回监听者是否已过期，所属的上下文结束也视为过期
+func (l *concurrentListener[EventKind, EventValue, ListenerID]) expired() bool {
+	if l.ctx != nil && l.ctx.Err() != nil {
+		return true
+	}
+	return !l.deadline.IsZero() && !l.clock.Now().Before(l.deadline)
+}
+
+// isRegistered 返回监听者是否仍注册在派发器中，已过期但尚未被移除的监听者同样视为已注册
+func (l *concurrentListener[EventKind, EventValue, ListenerID]) isRegistered() bool {
+	return !l.isRemoved()
+}
+
+// isActive 返回监听者是否仍然有效
+func (l *concurrentListener[EventKind, EventValue, ListenerID]) isActive() bool {
+	return !l.isRemoved() && !l.expired()
+}
+
+// markRemoved 标记为已移除，返回是否由本次调用完成标记
+func (l *concurrentListener[EventKind, EventValue, ListenerID]) markRemoved() bool {
+	if !atomic.CompareAndSwapInt32(&l.removed, 0, 1) {
+		return false
+	}
+	if l.stop != nil {
+		close(l.stop)
+	}
+	return true
+}
+
+// consume 消耗一次接收事件的次数
+// 返回是否消耗成功，以及是否为最后一次
+func (l *concurrentListener[EventKind, EventValue, ListenerID]) consume() (ok bool, last bool) {
+	for {
+		n := atomic.LoadInt32(&l.times)
+		if n < 0 {
+			return true, false
+		}
+		if n == 0 {
+			return false, false
+		}
+		if atomic.CompareAndSwapInt32(&l.times, n, n-1) {
+			return true, n == 1
+		}
+	}
+}
+
+// getStats 返回监听者的统计数据
+func (l *concurrentListener[EventKind, EventValue, ListenerID]) getStats() ListenerStats {
+	return ListenerStats{
+		Delivered:   atomic.LoadUint64(&l.delivered),
+		FilteredOut: atomic.LoadUint64(&l.filteredOut),
+		Errors:      atomic.LoadUint64(&l.errors),
+	}
+}
+
+// getReplayError 并发派发器不支持粘性事件，始终返回 nil
+func (l *concurrentListener[EventKind, EventValue, ListenerID]) getReplayError() error {
+	return nil
+}
+
+// dispatch 向监听者派发事件，派发前先经过过滤器判断
+// 返回派发后是否需要将其从容器中移除，以及监听者产生的错误
+func (l *concurrentListener[EventKind, EventValue, ListenerID]) dispatch(evt Event[EventKind, EventValue], opts *dispatcherOptions[EventKind, EventValue, ListenerID]) (bool, error) {
+	if l.isRemoved() {
+		return false, nil
+	}
+	if l.expired() {
+		// 已过期，不再接收事件
+		return l.markRemoved(), nil
+	}
+	if l.filter != nil {
+		// 被过滤掉的事件不消耗接收事件的次数
+		if accepted, err := l.accept(evt, opts); !accepted {
+			return false, err
+		}
+	}
+	ok, last := l.consume()
+	if !ok {
+		return false, nil
+	}
+	if last && !l.markRemoved() {
+		// 最后一次接收事件，需先抢占移除标记，保证不会被重复派发
+		return false, nil
+	}
+	err := l.invoke(evt, opts)
+	if err == ErrRemAfterDispatch && !last {
+		return l.markRemoved(), err
+	}
+	return last, err
+}
+
+// accept 以过滤器判断是否接收事件
+// 若开启了 recoverPanic，过滤器中的 panic 会被恢复为 *ListenerPanicError
+func (l *concurrentListener[EventKind, EventValue, ListenerID]) accept(evt Event[EventKind, EventValue], opts *dispatcherOptions[EventKind, EventValue, ListenerID]) (accepted bool, err error) {
+	if opts.recoverPanic {
+		defer func() {
+			if r := recover(); r != nil {
+				err = newListenerPanicError(l.id, evt.eventID, r)
+				atomic.AddUint64(&l.errors, 1)
+			}
+		}()
+	}
+	if !l.filter(evt) {
+		atomic.AddUint64(&l.filteredOut, 1)
+		return false, nil
+	}
+	return true, nil
+}
+
+// invoke 调用监听者回调
+// 若开启了 recoverPanic，回调中的 panic 会被恢复为 *ListenerPanicError
+func (l *concurrentListener[EventKind, EventValue, ListenerID]) invoke(evt Event[EventKind, EventValue], opts *dispatcherOptions[EventKind, EventValue, ListenerID]) (err error) {
+	atomic.AddUint64(&l.delivered, 1)
+	defer func() {
+		if opts.recoverPanic {
+			if r := recover(); r != nil {
+				err = newListenerPanicError(l.id, evt.eventID, r)
+			}
+		}
+		if isListenerError(err) {
+			atomic.AddUint64(&l.errors, 1)
+		}
+	}()
+	return l.callback(evt)
+}
+
+// concurrentListenerContainer 并发派发器的监听者容器
+// 仅在持有派发器锁时修改，监听者列表写时复制，修改后随即发布，一经发布便不再修改
+type concurrentListenerContainer[EventKind, EventValue, ListenerID comparable] struct {
+	listeners   []*concurrentListener[EventKind, EventValue, ListenerID]              // 监听者列表，不可变
+	listenerMap map[ListenerID]*concurrentListener[EventKind, EventValue, ListenerID] // 监听者 Map
+	published   atomic.Value                                                          // 已发布的监听者列表，供派发读取
+}
+
+func newConcurrentListenerContainer[EventKind, EventValue, ListenerID comparable]() *concurrentListenerContainer[EventKind, EventValue, ListenerID] {
+	ls := &concurrentListenerContainer[EventKind, EventValue, ListenerID]{
+		listenerMap: map[ListenerID]*concurrentListener[EventKind, EventValue, ListenerID]{},
+	}
+	ls.publish()
+	return ls
+}
+
+// load 加载已发布的监听者列表
+func (ls *concurrentListenerContainer[EventKind, EventValue, ListenerID]) load() []*concurrentListener[EventKind, EventValue, ListenerID] {
+	return ls.published.Load().([]*concurrentListener[EventKind, EventValue, ListenerID])
+}
+
+// publish 发布当前的监听者列表
+func (ls *concurrentListenerContainer[EventKind, EventValue, ListenerID]) publish() {
+	ls.published.Store(ls.listeners)
+}
+
+// addListener 添加监听者
+// 不能重复添加相同ID的监听者
+// 监听者按照优先级从高到低排列，相同优先级的监听者按照添加顺序排列
+func (ls *concurrentListenerContainer[EventKind, EventValue, ListenerID]) addListener(l *concurrentListener[EventKind, EventValue, ListenerID]) bool {
+	if old, ok := ls.listenerMap[l.id]; ok {
+		if !old.isRemoved() {
+			return false
+		}
+		// 已被派发标记移除但尚未清理，直接替换
+		ls.remListener(old.id, old)
+	}
+	ls.listeners = insertConcurrentListener(ls.listeners, l)
+	ls.listenerMap[l.id] = l
+	ls.publish()
+	return true
+}
+
+// setPriority 修改监听者的优先级，并重建监听者列表
+func (ls *concurrentListenerContainer[EventKind, EventValue, ListenerID]) setPriority(lID ListenerID, priority int) bool {
+	l, ok := ls.listenerMap[lID]
+	if !ok || l.isRemoved() {
+		return false
+	}
+	listeners := make([]*concurrentListener[EventKind, EventValue, ListenerID], 0, len(ls.listeners))
+	for _, v := range ls.listeners {
+		if v != l {
+			listeners = append(listeners, v)
+		}
+	}
+	l.priority = priority
+	ls.listeners = insertConcurrentListener(listeners, l)
+	ls.publish()
+	return true
+}
+
+// remListener 移除监听者
+// 若 target 不为空，仅当ID对应的监听者为 target 时移除
+// 返回是否由本次调用将监听者标记为移除，已被派发标记移除的监听者仅被清理，返回 false
+func (ls *concurrentListenerContainer[EventKind, EventValue, ListenerID]) remListener(lID ListenerID, target *concurrentListener[EventKind, EventValue, ListenerID]) bool {
+	l, ok := ls.listenerMap[lID]
+	if !ok || (target != nil && l != target) {
+		return false
+	}
+	rem := l.markRemoved()
+	delete(ls.listenerMap, lID)
+	var listeners []*concurrentListener[EventKind, EventValue, ListenerID]
+	if len(ls.listeners) > 1 {
+		listeners = make([]*concurrentListener[EventKind, EventValue, ListenerID], 0, len(ls.listeners)-1)
+		for _, v := range ls.listeners {
+			if v != l {
+				listeners = append(listeners, v)
+			}
+		}
+	}
+	ls.listeners = listeners
+	ls.publish()
+	return rem
+}
+
+// noListener 返回是否没有监听者
+func (ls *concurrentListenerContainer[EventKind, EventValue, ListenerID]) noListener() bool {
+	return len(ls.listeners) == 0
+}
+
+// clear 清理容器，移除所有监听者
+func (ls *concurrentListenerContainer[EventKind, EventValue, ListenerID]) clear() {
+	for _, l := range ls.listeners {
+		l.markRemoved()
+	}
+	ls.listeners = nil
+	ls.listenerMap = map[ListenerID]*concurrentListener[EventKind, EventValue, ListenerID]{}
+	ls.publish()
+}
+
+// insertConcurrentListener 按照优先级将监听者插入到列表副本中，返回新的列表
+// 监听者排在相同优先级监听者的末尾
+func insertConcurrentListener[EventKind, EventValue, ListenerID comparable](listeners []*concurrentListener[EventKind, EventValue, ListenerID], l *concurrentListener[EventKind, EventValue, ListenerID]) []*concurrentListener[EventKind, EventValue, ListenerID] {
+	i := len(listeners)
+	for i > 0 && listeners[i-1].priority < l.priority {
+		i--
+	}
+	newListeners := make([]*concurrentListener[EventKind, EventValue, ListenerID], 0, len(listeners)+1)
+	newListeners = append(newListeners, listeners[:i]...)
+	newListeners = append(newListeners, l)
+	return append(newListeners, listeners[i:]...)
+}
+
+// concurrentKindListenerContainer 并发派发器中按事件类型划分的监听者容器
+// 各监听者容器修改后各自发布，值类型监听者容器另以 sync.Map 发布，
+// 增删某个值的监听者时，只需复制该值对应的监听者列表
+type concurrentKindListenerContainer[EventKind, EventValue, ListenerID comparable] struct {
+	kindListeners  *concurrentListenerContainer[EventKind, EventValue, ListenerID]                // 类型事件监听者
+	valueListeners map[EventValue]*concurrentListenerContainer[EventKind, EventValue, ListenerID] // 值类事件监听者，仅在持有派发器锁时访问
+	publishedValue sync.Map                                                                       // 已发布的值类事件监听者容器，供派发读取
+}
+
+func newConcurrentKindListenerContainer[EventKind, EventValue, ListenerID comparable]() *concurrentKindListenerContainer[EventKind, EventValue, ListenerID] {
+	return &concurrentKindListenerContainer[EventKind, EventValue, ListenerID]{
+		kindListeners:  newConcurrentListenerContainer[EventKind, EventValue, ListenerID](),
+		valueListeners: map[EventValue]*concurrentListenerContainer[EventKind, EventValue, ListenerID]{},
+	}
+}
+
+// loadValueListeners 加载已发布的值类事件监听者列表
+func (kls *concurrentKindListenerContainer[EventKind, EventValue, ListenerID]) loadValueListeners(value EventValue) []*concurrentListener[EventKind, EventValue, ListenerID] {
+	lc, ok := kls.publishedValue.Load(value)
+	if !ok {
+		return nil
+	}
+	return lc.(*concurrentListenerContainer[EventKind, EventValue, ListenerID]).load()
+}
+
+// addValueListener 添加值类型事件监听者
+func (kls *concurrentKindListenerContainer[EventKind, EventValue, ListenerID]) addValueListener(value EventValue, l *concurrentListener[EventKind, EventValue, ListenerID]) bool {
+	lc := kls.valueListeners[value]
+	if lc == nil {
+		lc = newConcurrentListenerContainer[EventKind, EventValue, ListenerID]()
+		kls.valueListeners[value] = lc
+		kls.publishedValue.Store(value, lc)
+	}
+	add := lc.addListener(l)
+	kls.tidyValueListeners(value, lc)
+	return add
+}
+
+// remValueListener 移除值类型事件监听者
+func (kls *concurrentKindListenerContainer[EventKind, EventValue, ListenerID]) remValueListener(value EventValue, lID ListenerID, target *concurrentListener[EventKind, EventValue, ListenerID]) bool {
+	lc := kls.valueListeners[value]
+	if lc == nil {
+		return false
+	}
+	rem := lc.remListener(lID, target)
+	kls.tidyValueListeners(value, lc)
+	return rem
+}
+
+// tidyValueListeners 若值对应的容器中已没有监听者，将其移除
+func (kls *concurrentKindListenerContainer[EventKind, EventValue, ListenerID]) tidyValueListeners(value EventValue, lc *concurrentListenerContainer[EventKind, EventValue, ListenerID]) {
+	if lc.noListener() {
+		delete(kls.valueListeners, value)
+		kls.publishedValue.Delete(value)
+	}
+}
+
+// noListener 返回是否没有监听者
+func (kls *concurrentKindListenerContainer[EventKind, EventValue, ListenerID]) noListener() bool {
+	return kls.kindListeners.noListener() && len(kls.valueListeners) == 0
+}
+
+// clear 清理容器，移除所有监听者
+func (kls *concurrentKindListenerContainer[EventKind, EventValue, ListenerID]) clear() {
+	kls.kindListeners.clear()
+	for v, lc := range kls.valueListeners {
+		lc.clear()
+		kls.publishedValue.Delete(v)
+	}
+	kls.valueListeners = map[EventValue]*concurrentListenerContainer[EventKind, EventValue, ListenerID]{}
+}
@@ -0,0 +1,310 @@
+package gevent
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConcurrentDispatcher(t *testing.T) {
+	dispatcher := NewConcurrentDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+	eventVal := testEV(1)
+	var value int64
+
+	dispatcher.AddKindListener(eventType, 1, func(e testEvent) error {
+		atomic.AddInt64(&value, 1)
+		return nil
+	})
+	dispatcher.AddValueListener(testEventID{eventType, eventVal}, 2, func(e testEvent) error {
+		atomic.AddInt64(&value, 2)
+		return nil
+	})
+	if _, ok := dispatcher.AddKindListener(eventType, 1, func(e testEvent) error { return nil }); ok {
+		t.Fatal("repeated listener must not be added")
+	}
+	if err := dispatcher.Dispatch(testEventID{eventType, eventVal}, nil); err != nil {
+		t.Fatal("there must no error")
+	}
+	if value != 3 {
+		t.Fatal("value must be", 3)
+	}
+
+	// 修改某个值的监听者时，不影响其它值已发布的监听者列表
+	dispatcher.AddValueListener(testEventID{eventType, 2}, 3, func(e testEvent) error { return nil })
+	klc := dispatcher.loadKindListenerContainers()[eventType]
+	published := klc.loadValueListeners(2)
+	dispatcher.AddValueListener(testEventID{eventType, eventVal}, 3, func(e testEvent) error { return nil })
+	if len(klc.loadValueListeners(eventVal)) != 2 || &klc.loadValueListeners(2)[0] != &published[0] {
+		t.Fatal("only listeners of value", eventVal, "must be republished")
+	}
+	if !dispatcher.RemValueListener(testEventID{eventType, 2}, 3) || klc.loadValueListeners(2) != nil {
+		t.Fatal("value listeners must be removed")
+	}
+	if _, ok := klc.publishedValue.Load(testEV(2)); ok {
+		t.Fatal("value listeners must be unpublished")
+	}
+
+	dispatcher.RemKindListener(eventType, 1)
+	dispatcher.RemValueListener(testEventID{eventType, eventVal}, 2)
+	dispatcher.RemValueListener(testEventID{eventType, eventVal}, 3)
+	if len(dispatcher.loadKindListenerContainers()) != 0 {
+		t.Fatal("handlers must clear")
+	}
+}
+
+func TestConcurrentDispatcherOnce(t *testing.T) {
+	dispatcher := NewConcurrentDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+	var value int64
+
+	dispatcher.AddKindListener(eventType, 1, func(e testEvent) error {
+		atomic.AddInt64(&value, 1)
+		return nil
+	}, WithOnce())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dispatcher.Dispatch(testEventID{eventType, 0}, nil)
+		}()
+	}
+	wg.Wait()
+	if value != 1 {
+		t.Fatal("once listener must be dispatched only once, got", value)
+	}
+	if len(dispatcher.loadKindListenerContainers()) != 0 {
+		t.Fatal("handlers must clear")
+	}
+}
+
+func TestConcurrentDispatcherRace(t *testing.T) {
+	dispatcher := NewConcurrentDispatcher[testET, testEV, testLID]()
+	maxEventType := 4
+	maxEventVal := 4
+	var wg sync.WaitGroup
+
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				evtId := testEventID{testET(i % maxEventType), testEV(i % maxEventVal)}
+				lID := testLID(g*1000 + i)
+				switch i % 5 {
+				case 0:
+					dispatcher.AddKindListener(evtId.Kind, lID, func(e testEvent) error {
+						// 回调中可以安全地调用派发器
+						dispatcher.RemKindListener(e.EventID().Kind, lID)
+						return nil
+					})
+				case 1:
+					dispatcher.AddValueListener(evtId, lID, func(e testEvent) error {
+						return ErrRemAfterDispatch
+					})
+				case 2:
+					dispatcher.RemKindListener(evtId.Kind, testLID(g*1000+i-2))
+				case 3:
+					dispatcher.AddValueListener(evtId, lID, func(e testEvent) error { return nil }, WithOnce())
+				default:
+					dispatcher.Dispatch(evtId, nil, i)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	dispatcher.Clear()
+	if len(dispatcher.loadKindListenerContainers()) != 0 {
+		t.Fatal("handlers must clear")
+	}
+}
+
+func TestConcurrentDispatcherPriority(t *testing.T) {
+	dispatcher := NewConcurrentDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+	var order []testLID
+	for _, lID := range []testLID{1, 2, 3} {
+		lID := lID
+		dispatcher.AddKindListener(eventType, lID, func(e testEvent) error {
+			order = append(order, lID)
+			return nil
+		}, WithPriority(int(lID)))
+	}
+	dispatcher.SetKindListenerPriority(eventType, 1, 10)
+	dispatcher.Dispatch(testEventID{eventType, 1}, nil)
+	if len(order) != 3 || order[0] != 1 || order[1] != 3 || order[2] != 2 {
+		t.Fatal("order must be [1 3 2], got", order)
+	}
+}
+
+func TestConcurrentDispatcherLifetime(t *testing.T) {
+	clock := newTestClock()
+	dispatcher := NewConcurrentDispatcher(WithClock[testET, testEV, testLID](clock))
+	eventType := testET(1)
+	var times, ttl int64
+
+	dispatcher.AddKindListener(eventType, 1, func(e testEvent) error {
+		atomic.AddInt64(&times, 1)
+		return nil
+	}, WithTimes(10))
+	dispatcher.AddKindListener(eventType, 2, func(e testEvent) error {
+		atomic.AddInt64(&ttl, 1)
+		return nil
+	}, WithTTL(time.Second))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				dispatcher.Dispatch(testEventID{eventType, 1}, nil)
+			}
+		}()
+	}
+	wg.Wait()
+	if times != 10 {
+		t.Fatal("times must be", 10)
+	}
+	if ttl != 80 {
+		t.Fatal("ttl must be", 80)
+	}
+
+	clock.Advance(time.Second)
+	dispatcher.Dispatch(testEventID{eventType, 1}, nil)
+	if ttl != 80 {
+		t.Fatal("ttl must be", 80)
+	}
+	if len(dispatcher.loadKindListenerContainers()) != 0 {
+		t.Fatal("kind listener containers must be empty")
+	}
+}
+
+func TestConcurrentDispatcherContext(t *testing.T) {
+	dispatcher := NewConcurrentDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+	var value int64
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, _ := dispatcher.AddKindListenerContext(ctx, eventType, 1, func(e testEvent) error {
+		atomic.AddInt64(&value, 1)
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				dispatcher.Dispatch(testEventID{eventType, 1}, nil)
+			}
+		}()
+	}
+	cancel()
+	wg.Wait()
+
+	delivered := atomic.LoadInt64(&value)
+	dispatcher.Dispatch(testEventID{eventType, 1}, nil)
+	if atomic.LoadInt64(&value) != delivered {
+		t.Fatal("value must be", delivered)
+	}
+	if sub.Active() || len(dispatcher.loadKindListenerContainers()) != 0 {
+		t.Fatal("context listener must be removed")
+	}
+}
+
+func TestConcurrentDispatcherContextRemoval(t *testing.T) {
+	dispatcher := NewConcurrentDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+
+	// 上下文结束后，无需派发事件，监听者即被移除
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, _ := dispatcher.AddValueListenerContext(ctx, testEventID{eventType, 1}, 1, func(e testEvent) error {
+		return nil
+	})
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for len(dispatcher.loadKindListenerContainers()) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("context listener must be removed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if sub.Active() || sub.Unsubscribe() {
+		t.Fatal("context listener must be removed")
+	}
+
+	// 先取消订阅时结束对上下文的监听
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	sub, _ = dispatcher.AddKindListenerContext(ctx, eventType, 2, func(e testEvent) error {
+		return nil
+	})
+	l := sub.handle.(*concurrentListener[testET, testEV, testLID])
+	if !sub.Unsubscribe() {
+		t.Fatal("listener must be removed")
+	}
+	select {
+	case <-l.stop:
+	default:
+		t.Fatal("context watch must be stopped")
+	}
+}
+
+func TestConcurrentUnsubscribeAfterLastDispatch(t *testing.T) {
+	dispatcher := NewConcurrentDispatcher[testET, testEV, testLID]()
+	sub, _ := dispatcher.AddKindListener(1, 1, func(e testEvent) error { return nil }, WithOnce())
+	l := sub.handle.(*concurrentListener[testET, testEV, testLID])
+
+	// 模拟取消订阅与派发竞争：派发已抢占最后一次接收事件的移除标记，尚未清理
+	if ok, last := l.consume(); !ok || !last || !l.markRemoved() {
+		t.Fatal("listener must be marked removed by dispatch")
+	}
+	if dispatcher.unsubscribe(sub) {
+		t.Fatal("unsubscribe must report false after dispatch removed the listener")
+	}
+	if len(dispatcher.loadKindListenerContainers()) != 0 {
+		t.Fatal("removed listener must be cleaned up")
+	}
+}
+
+func TestConcurrentDispatcherFilter(t *testing.T) {
+	dispatcher := NewConcurrentDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+	var value int64
+
+	sub, _ := dispatcher.AddFilteredListener(eventType, 1, func(e testEvent) bool {
+		return e.EventID().Value%2 == 0
+	}, func(e testEvent) error {
+		atomic.AddInt64(&value, 1)
+		return nil
+	}, WithTimes(50))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				dispatcher.Dispatch(testEventID{eventType, testEV(j)}, nil)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if value != 50 {
+		t.Fatal("value must be", 50)
+	}
+	if stats := sub.Stats(); stats.Delivered != 50 || stats.FilteredOut == 0 {
+		t.Fatal("stats mismatch", stats)
+	}
+	if sub.Active() {
+		t.Fatal("subscription must be inactive")
+	}
+}
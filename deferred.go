@@ -0,0 +1,112 @@
+package gevent
+
+import (
+	"container/list"
+	"errors"
+)
+
+// ErrCascadeDepthExceeded 级联深度超出上限
+// 派发延迟事件的过程中入队的事件，其级联深度超出 DeferredOptions.MaxCascadeDepth 时返回
+var ErrCascadeDepthExceeded = errors.New("deferred event cascade depth exceeded")
+
+// DeferredOptions 延迟派发器选项
+type DeferredOptions[EventKind, EventValue comparable] struct {
+	Budget          int                                      // 每次 Flush 最多派发的事件数，小于等于 0 时不限，超出的事件留待下次 Flush 派发
+	SameFrame       bool                                     // Flush 过程中入队的事件是否在本次 Flush 中派发，默认留待下次 Flush 派发
+	MaxCascadeDepth int                                      // 级联深度上限，小于等于 0 时不限
+	ErrorHandler    EventErrorHandler[EventKind, EventValue] // 派发出错时在 Flush 中回调，为空时丢弃错误
+}
+
+// deferredEvent 等待派发的延迟事件
+type deferredEvent[EventKind, EventValue comparable] struct {
+	evtId     EventID[EventKind, EventValue] // 事件ID
+	generator interface{}                    // 事件产生者
+	param     []interface{}                  // 参数
+	depth     int                            // 级联深度，在 Flush 之外入队的事件为 0
+}
+
+// DeferredDispatcher 延迟事件派发器
+// 在 Dispatcher 的基础上，提供将事件放入队列，在调用 Flush 时统一派发的能力，
+// 可以在游戏循环等场景中选择合适的时机派发事件，避免在深层调用中同步派发导致的重入问题
+// 监听者的添加、移除以及同步派发，沿用 Dispatcher 的接口；与 Dispatcher 相同，不能被多个 goroutine 同时调用
+type DeferredDispatcher[EventKind, EventValue, ListenerID comparable] struct {
+	*Dispatcher[EventKind, EventValue, ListenerID]
+
+	options  DeferredOptions[EventKind, EventValue] // 选项
+	queue    *list.List                             // 事件队列
+	flushing bool                                   // 是否正在 Flush
+	depth    int                                    // 当前入队事件的级联深度
+}
+
+func NewDeferredDispatcher[EventKind, EventValue, ListenerID comparable](options DeferredOptions[EventKind, EventValue], opts ...DispatcherOption[EventKind, EventValue, ListenerID]) *DeferredDispatcher[EventKind, EventValue, ListenerID] {
+	return &DeferredDispatcher[EventKind, EventValue, ListenerID]{
+		Dispatcher: NewDispatcher(opts...),
+		options:    options,
+		queue:      list.New(),
+	}
+}
+
+// Queue 将事件放入队列，等待 Flush 时派发
+// 派发延迟事件的过程中入队的事件，级联深度为该延迟事件的深度加一，超出上限时返回 ErrCascadeDepthExceeded
+func (d *DeferredDispatcher[EventKind, EventValue, ListenerID]) Queue(evtId EventID[EventKind, EventValue], generator interface{}, param ...interface{}) error {
+	if d.options.MaxCascadeDepth > 0 && d.depth > d.options.MaxCascadeDepth {
+		return ErrCascadeDepthExceeded
+	}
+	d.queue.PushBack(&deferredEvent[EventKind, EventValue]{
+		evtId:     evtId,
+		generator: generator,
+		param:     param,
+		depth:     d.depth,
+	})
+	return nil
+}
+
+// Pending 返回队列中等待派发的事件数量
+func (d *DeferredDispatcher[EventKind, EventValue, ListenerID]) Pending() int {
+	return d.queue.Len()
+}
+
+// Discard 丢弃队列中所有等待派发的事件，返回丢弃的数量
+func (d *DeferredDispatcher[EventKind, EventValue, ListenerID]) Discard() int {
+	n := d.queue.Len()
+	d.queue.Init()
+	return n
+}
+
+// Flush 按入队顺序派发队列中的事件，返回派发的事件数量
+// 受 DeferredOptions.Budget 限制，未派发的事件留待下次 Flush；Flush 过程中再次调用 Flush 不会派发任何事件
+// 指定 SameFrame 时，Flush 过程中入队的事件也在本次派发，此时应通过 Budget 或 MaxCascadeDepth 避免事件无限级联
+func (d *DeferredDispatcher[EventKind, EventValue, ListenerID]) Flush() int {
+	if d.flushing {
+		return 0
+	}
+	d.flushing = true
+	defer func() {
+		d.flushing = false
+		d.depth = 0
+	}()
+
+	// 未指定 SameFrame 时，只派发本次 Flush 开始前入队的事件；limit 小于 0 表示不限
+	limit := -1
+	if !d.options.SameFrame {
+		limit = d.queue.Len()
+	}
+	if d.options.Budget > 0 && (limit < 0 || d.options.Budget < limit) {
+		limit = d.options.Budget
+	}
+
+	n := 0
+	for limit < 0 || n < limit {
+		elem := d.queue.Front()
+		if elem == nil {
+			break
+		}
+		evt := d.queue.Remove(elem).(*deferredEvent[EventKind, EventValue])
+		n++
+		d.depth = evt.depth + 1
+		if err := d.Dispatch(evt.evtId, evt.generator, evt.param...); err != nil && d.options.ErrorHandler != nil {
+			d.options.ErrorHandler(evt.evtId, err)
+		}
+	}
+	return n
+}
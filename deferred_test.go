@@ -0,0 +1,128 @@
+package gevent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDeferredDispatcher(t *testing.T) {
+	dispatcher := NewDeferredDispatcher[testET, testEV, testLID](DeferredOptions[testET, testEV]{})
+	var values []testEV
+
+	dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		values = append(values, e.EventID().Value)
+		if e.EventID().Value < 3 {
+			// Flush 过程中入队的事件默认留待下次 Flush
+			if err := dispatcher.Queue(testEventID{1, e.EventID().Value + 10}, nil); err != nil {
+				t.Fatal("there must no error")
+			}
+		}
+		return nil
+	})
+
+	for i := 1; i <= 3; i++ {
+		dispatcher.Queue(testEventID{1, testEV(i)}, nil)
+	}
+	if len(values) != 0 || dispatcher.Pending() != 3 {
+		t.Fatal("events must be deferred")
+	}
+
+	if n := dispatcher.Flush(); n != 3 {
+		t.Fatal("flushed count must be", 3)
+	}
+	if len(values) != 3 || values[0] != 1 || values[1] != 2 || values[2] != 3 {
+		t.Fatal("values must be [1 2 3], got", values)
+	}
+	if dispatcher.Pending() != 2 {
+		t.Fatal("pending count must be", 2)
+	}
+
+	if n := dispatcher.Flush(); n != 2 {
+		t.Fatal("flushed count must be", 2)
+	}
+	if len(values) != 5 || values[3] != 11 || values[4] != 12 {
+		t.Fatal("values must be [1 2 3 11 12], got", values)
+	}
+	if dispatcher.Flush() != 0 {
+		t.Fatal("flushed count must be", 0)
+	}
+}
+
+func TestDeferredDispatcherBudget(t *testing.T) {
+	testErr := errors.New("test error")
+	var handled int
+	dispatcher := NewDeferredDispatcher[testET, testEV, testLID](DeferredOptions[testET, testEV]{
+		Budget: 2,
+		ErrorHandler: func(evtId testEventID, err error) {
+			if !errors.Is(err, testErr) {
+				t.Fatal("error must be test error")
+			}
+			handled++
+		},
+	})
+	value := 0
+
+	dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		value++
+		// Flush 过程中再次 Flush 不会派发任何事件
+		if dispatcher.Flush() != 0 {
+			t.Fatal("nested flush must not dispatch")
+		}
+		return testErr
+	})
+
+	for i := 0; i < 5; i++ {
+		dispatcher.Queue(testEventID{1, 1}, nil)
+	}
+	for _, expected := range []int{2, 2, 1, 0} {
+		if n := dispatcher.Flush(); n != expected {
+			t.Fatal("flushed count must be", expected)
+		}
+	}
+	if value != 5 || handled != 5 {
+		t.Fatal("value must be", 5)
+	}
+
+	dispatcher.Queue(testEventID{1, 1}, nil)
+	if dispatcher.Discard() != 1 || dispatcher.Flush() != 0 {
+		t.Fatal("queued events must be discarded")
+	}
+}
+
+func TestDeferredDispatcherCascade(t *testing.T) {
+	dispatcher := NewDeferredDispatcher[testET, testEV, testLID](DeferredOptions[testET, testEV]{
+		SameFrame:       true,
+		MaxCascadeDepth: 2,
+	})
+	var depths []testEV
+	var cascadeErr error
+
+	dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		depth := e.EventID().Value
+		depths = append(depths, depth)
+		if err := dispatcher.Queue(testEventID{1, depth + 1}, nil); err != nil {
+			cascadeErr = err
+		}
+		return nil
+	})
+
+	dispatcher.Queue(testEventID{1, 0}, nil)
+	// 同一帧内派发级联的事件，直到超出级联深度上限
+	if n := dispatcher.Flush(); n != 3 {
+		t.Fatal("flushed count must be", 3)
+	}
+	if len(depths) != 3 || depths[2] != 2 {
+		t.Fatal("depths must be [0 1 2], got", depths)
+	}
+	if cascadeErr != ErrCascadeDepthExceeded {
+		t.Fatal("error must be ErrCascadeDepthExceeded")
+	}
+	if dispatcher.Pending() != 0 {
+		t.Fatal("pending count must be", 0)
+	}
+
+	// Flush 之外入队的事件重新从深度 0 开始
+	if err := dispatcher.Queue(testEventID{1, 0}, nil); err != nil {
+		t.Fatal("there must no error")
+	}
+}
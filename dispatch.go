@@ -1,70 +1,316 @@
 package gevent
 
+import (
+	"container/list"
+	"context"
+)
+
 // Dispatcher 事件派发器
 // 用于为特定类型或特定值类型的事件添加监听者，并在产生事件时将事件派发给监听者
+// 事件依次派发给全局监听者、多类型监听者、类型监听者、值类型监听者，
+// 同一阶段内按照优先级及添加顺序派发，任一阶段停止传播或中止派发后，不再派发给后续阶段
+// 指定了 WithKindParent 时，最后依次冒泡派发给监听各级祖先类型的多类型监听者及类型监听者
+// 派发器可以通过 SetParent 链接成派发链，事件在派发链中的传递参见 SetParent
+// 派发过程中添加的监听者会被挂起，在最外层的派发完成后才真正添加，
+// 因此不会接收到正在派发的事件，也不会接收到该过程中嵌套派发的事件
 type Dispatcher[EventKind, EventValue, ListenerID comparable] struct {
+	globalListeners        *listenerContainer[EventKind, EventValue, ListenerID]                   // 全局监听者
+	multiKindListeners     *listenerContainer[EventKind, EventValue, ListenerID]                   // 多类型监听者
 	kindListenerContainers map[EventKind]*kindListenerContainer[EventKind, EventValue, ListenerID] // 按照事件类型划分的监听者容器
+	captureListeners       map[EventKind]*listenerContainer[EventKind, EventValue, ListenerID]     // 按照事件类型划分的捕获监听者
+	parent                 *Dispatcher[EventKind, EventValue, ListenerID]                          // 父派发器
+	pendingAddList         *list.List                                                              // 挂起添加列表，等待在事件派发完成后被添加的监听者
+	index                  *listenerIndex[EventKind, EventValue, ListenerID]                       // 监听者反向索引
+	pacedListeners         []*listener[EventKind, EventValue, ListenerID]                          // 节流的监听者，按照添加顺序排列，由 Tick 投递缓冲的事件
+	contextListeners       *list.List                                                              // 与上下文绑定的监听者，按照添加顺序排列，监听者被移除时随之移出
+	contextSweepLen        int                                                                     // 添加与上下文绑定的监听者时，达到该数量即移除上下文已结束的监听者
+	sticky                 map[EventKind]map[EventValue]*stickyEvent[EventKind, EventValue]        // 按照事件ID保留的粘性事件
+	stickySeq              uint64                                                                  // 下一个粘性事件的保留序号
 	dispatching            int                                                                     // 派发状态计数
+	options                dispatcherOptions[EventKind, EventValue, ListenerID]                    // 选项
 }
 
-func NewDispatcher[EventKind, EventValue, ListenerID comparable]() *Dispatcher[EventKind, EventValue, ListenerID] {
+func NewDispatcher[EventKind, EventValue, ListenerID comparable](opts ...DispatcherOption[EventKind, EventValue, ListenerID]) *Dispatcher[EventKind, EventValue, ListenerID] {
 	return &Dispatcher[EventKind, EventValue, ListenerID]{
 		kindListenerContainers: map[EventKind]*kindListenerContainer[EventKind, EventValue, ListenerID]{},
+		index:                  newListenerIndex[EventKind, EventValue, ListenerID](),
+		options:                newDispatcherOptions(opts),
 	}
 }
 
 // AddKindListener 添加事件类型监听者
-func (d *Dispatcher[EventKind, EventValue, ListenerID]) AddKindListener(evtKind EventKind, lID ListenerID, callback ListenerCallback[EventKind, EventValue], once ...bool) bool {
-	if callback == nil {
-		panic("listener callback nil")
+// 添加成功时返回对应的订阅
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) AddKindListener(evtKind EventKind, lID ListenerID, callback ListenerCallback[EventKind, EventValue], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return d.addListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeKind,
+		EventID:    EventID[EventKind, EventValue]{Kind: evtKind},
+		ListenerID: lID,
+	}, callback, nil, newListenerOptions(opts))
+}
+
+// AddValueListener 添加值类型监听者
+// 添加成功时返回对应的订阅
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) AddValueListener(evtId EventID[EventKind, EventValue], lID ListenerID, callback ListenerCallback[EventKind, EventValue], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return d.addListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeValue,
+		EventID:    evtId,
+		ListenerID: lID,
+	}, callback, nil, newListenerOptions(opts))
+}
+
+// AddKindListenerContext 添加与 ctx 绑定的事件类型监听者
+// ctx 结束后监听者失效，不再接收事件；ctx 已结束时不会添加监听者
+// 派发器不会主动监听 ctx，失效的监听者在下次派发到其所在的容器、调用 Tick，
+// 或与上下文绑定的监听者数量累积翻倍后再添加此类监听者时被移除，因此失效的监听者不会无限累积
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) AddKindListenerContext(ctx context.Context, evtKind EventKind, lID ListenerID, callback ListenerCallback[EventKind, EventValue], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	o, ok := newContextListenerOptions(ctx, opts)
+	if !ok {
+		return nil, false
 	}
-	once_ := false
-	if len(once) > 0 {
-		once_ = once[0]
+	return d.addListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeKind,
+		EventID:    EventID[EventKind, EventValue]{Kind: evtKind},
+		ListenerID: lID,
+	}, callback, nil, o)
+}
+
+// AddValueListenerContext 添加与 ctx 绑定的值类型监听者
+// 参见 AddKindListenerContext
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) AddValueListenerContext(ctx context.Context, evtId EventID[EventKind, EventValue], lID ListenerID, callback ListenerCallback[EventKind, EventValue], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	o, ok := newContextListenerOptions(ctx, opts)
+	if !ok {
+		return nil, false
 	}
-	l := newListener(lID, callback, once_)
-	klc := d.addORGetKindListeners(evtKind)
-	return klc.addKindListener(l)
+	return d.addListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeValue,
+		EventID:    evtId,
+		ListenerID: lID,
+	}, callback, nil, o)
 }
 
-// AddValueListener 添加值类型监听者
-func (d *Dispatcher[EventKind, EventValue, ListenerID]) AddValueListener(evtId EventID[EventKind, EventValue], lID ListenerID, callback ListenerCallback[EventKind, EventValue], once ...bool) bool {
+// AddFilteredListener 添加带过滤器的事件类型监听者
+// 派发器在调用回调前以 filter 判断是否接收事件，被过滤掉的事件不计入接收次数
+// 监听者作为事件类型监听者添加，可通过 RemKindListener 移除
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) AddFilteredListener(evtKind EventKind, lID ListenerID, filter EventFilter[EventKind, EventValue], callback ListenerCallback[EventKind, EventValue], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	if filter == nil {
+		panic("listener filter nil")
+	}
+	return d.addListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeKind,
+		EventID:    EventID[EventKind, EventValue]{Kind: evtKind},
+		ListenerID: lID,
+	}, callback, filter, newListenerOptions(opts))
+}
+
+// AddGlobalListener 添加全局监听者，监听所有类型的事件
+// 全局监听者先于其它监听者接收事件
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) AddGlobalListener(lID ListenerID, callback ListenerCallback[EventKind, EventValue], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return d.addListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeGlobal,
+		ListenerID: lID,
+	}, callback, nil, newListenerOptions(opts))
+}
+
+// AddMultiKindListener 添加多类型监听者，监听 evtKinds 中所有类型的事件
+// 多类型监听者作为一个整体添加及移除，在全局监听者之后、类型监听者之前接收事件
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) AddMultiKindListener(evtKinds []EventKind, lID ListenerID, callback ListenerCallback[EventKind, EventValue], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	if len(evtKinds) == 0 {
+		panic("listener kinds empty")
+	}
 	if callback == nil {
 		panic("listener callback nil")
 	}
-	once_ := false
-	if len(once) > 0 {
-		once_ = once[0]
+	l := newListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeMultiKind,
+		ListenerID: lID,
+	}, callback, nil, newListenerOptions(opts), d.options.clock)
+	l.kinds = make(map[EventKind]struct{}, len(evtKinds))
+	for _, kind := range evtKinds {
+		l.kinds[kind] = struct{}{}
 	}
-	l := newListener(lID, callback, once_)
-	klc := d.addORGetKindListeners(evtId.Kind)
-	return klc.addValueListener(evtId.Value, l)
+	return d.subscribe(l)
 }
 
 // RemKindListener 移除事件类型监听者
 func (d *Dispatcher[EventKind, EventValue, ListenerID]) RemKindListener(evtKind EventKind, lID ListenerID) bool {
-	klc := d.kindListenerContainers[evtKind]
+	return d.remListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeKind,
+		EventID:    EventID[EventKind, EventValue]{Kind: evtKind},
+		ListenerID: lID,
+	})
+}
+
+// RemValueListener 移除值类型监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) RemValueListener(evtId EventID[EventKind, EventValue], lID ListenerID) bool {
+	return d.remListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeValue,
+		EventID:    evtId,
+		ListenerID: lID,
+	})
+}
+
+// RemGlobalListener 移除全局监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) RemGlobalListener(lID ListenerID) bool {
+	return d.remListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeGlobal,
+		ListenerID: lID,
+	})
+}
+
+// RemMultiKindListener 移除多类型监听者，监听的所有类型一并移除
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) RemMultiKindListener(lID ListenerID) bool {
+	return d.remListener(Registration[EventKind, EventValue, ListenerID]{
+		Scope:      ListenerScopeMultiKind,
+		ListenerID: lID,
+	})
+}
+
+// addListener 按照注册信息添加监听者
+// 派发过程中添加的监听者会被挂起
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) addListener(reg Registration[EventKind, EventValue, ListenerID], callback ListenerCallback[EventKind, EventValue], filter EventFilter[EventKind, EventValue], opts listenerOptions) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	if callback == nil {
+		panic("listener callback nil")
+	}
+	return d.subscribe(newListener(reg, callback, filter, opts, d.options.clock))
+}
+
+// subscribe 添加监听者，返回对应的订阅
+// 派发过程中添加的监听者会被挂起
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) subscribe(l *listener[EventKind, EventValue, ListenerID]) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	// 已失效但尚未被移除的监听者不再占用相同的注册
+	d.remExpired(l.reg)
+	if l.ctx != nil {
+		d.sweepContextListeners()
+	}
+	var add bool
+	if d.dispatching > 0 {
+		add = d.pendingAddListener(l)
+	} else {
+		add = d.directAddListener(l)
+	}
+	if !add {
+		return nil, false
+	}
+	d.index.add(l)
+	if l.pacing != nil {
+		d.pacedListeners = append(d.pacedListeners, l)
+	}
+	if l.ctx != nil {
+		d.addContextListener(l)
+	}
+	s := newSubscription[EventKind, EventValue, ListenerID](d, l, l.reg)
+	if d.dispatching == 0 {
+		d.replaySticky(l)
+	}
+	return s, true
+}
+
+// directAddListener 直接添加监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) directAddListener(l *listener[EventKind, EventValue, ListenerID]) bool {
+	switch l.reg.Scope {
+	case ListenerScopeCapture:
+		if d.captureListeners == nil {
+			d.captureListeners = map[EventKind]*listenerContainer[EventKind, EventValue, ListenerID]{}
+		}
+		lc := d.captureListeners[l.reg.EventID.Kind]
+		if lc == nil {
+			lc = newListenerContainer[EventKind, EventValue, ListenerID]()
+			d.captureListeners[l.reg.EventID.Kind] = lc
+		}
+		return lc.addListener(l)
+	case ListenerScopeGlobal:
+		if d.globalListeners == nil {
+			d.globalListeners = newListenerContainer[EventKind, EventValue, ListenerID]()
+		}
+		return d.globalListeners.addListener(l)
+	case ListenerScopeMultiKind:
+		if d.multiKindListeners == nil {
+			d.multiKindListeners = newListenerContainer[EventKind, EventValue, ListenerID]()
+		}
+		return d.multiKindListeners.addListener(l)
+	}
+	klc := d.addORGetKindListeners(l.reg.EventID.Kind)
+	if l.reg.Scope == ListenerScopeValue {
+		return klc.addValueListener(l.reg.EventID.Value, l)
+	}
+	return klc.addKindListener(l)
+}
+
+// remListener 按照注册信息移除监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) remListener(reg Registration[EventKind, EventValue, ListenerID]) bool {
+	if d.remPendingAdd(reg) {
+		return true
+	}
+	switch reg.Scope {
+	case ListenerScopeCapture:
+		return d.remCaptureListener(reg.EventID.Kind, reg.ListenerID)
+	case ListenerScopeGlobal:
+		return remContainerListener(&d.globalListeners, reg.ListenerID)
+	case ListenerScopeMultiKind:
+		return remContainerListener(&d.multiKindListeners, reg.ListenerID)
+	}
+	klc := d.kindListenerContainers[reg.EventID.Kind]
 	if klc == nil {
 		return false
 	}
-	rem := klc.remKindListener(lID)
+	var rem bool
+	if reg.Scope == ListenerScopeValue {
+		rem = klc.remValueListener(reg.EventID.Value, reg.ListenerID)
+	} else {
+		rem = klc.remKindListener(reg.ListenerID)
+	}
 	if klc.noListener() {
-		delete(d.kindListenerContainers, evtKind)
+		delete(d.kindListenerContainers, reg.EventID.Kind)
 	}
 	return rem
 }
 
-// RemValueListener 移除值类型监听者
-func (d *Dispatcher[EventKind, EventValue, ListenerID]) RemValueListener(evtId EventID[EventKind, EventValue], lID ListenerID) bool {
+// unsubscribe 移除订阅对应的监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) unsubscribe(s *Subscription[EventKind, EventValue, ListenerID]) bool {
+	return d.remListener(s.registration)
+}
+
+// RemListener 移除监听者ID对应的所有监听者，包括全局监听者、多类型监听者、类型监听者、值类型监听者及捕获监听者
+// 派发过程中移除时，与 RemKindListener 等相同，监听者被挂起等待派发完成后移除
+// 返回是否移除了任意监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) RemListener(lID ListenerID) bool {
+	rem := false
+	for _, reg := range d.index.registrations(lID, true) {
+		if d.remListener(reg) {
+			rem = true
+		}
+	}
+	return rem
+}
+
+// ListenersOf 返回监听者ID对应的所有注册信息，按照添加顺序排列
+// 包括派发过程中挂起添加的监听者，不包括已挂起移除或已过期的监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) ListenersOf(lID ListenerID) []Registration[EventKind, EventValue, ListenerID] {
+	return d.index.registrations(lID, false)
+}
+
+// SetKindListenerPriority 修改事件类型监听者的优先级
+// 修改后监听者排在相同优先级监听者的末尾；派发过程中修改时，在派发完成后调整顺序
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) SetKindListenerPriority(evtKind EventKind, lID ListenerID, priority int) bool {
+	klc := d.kindListenerContainers[evtKind]
+	if klc == nil || klc.kindListeners == nil {
+		return false
+	}
+	return klc.kindListeners.setPriority(lID, priority)
+}
+
+// SetValueListenerPriority 修改值类型监听者的优先级
+// 修改后监听者排在相同优先级监听者的末尾；派发过程中修改时，在派发完成后调整顺序
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) SetValueListenerPriority(evtId EventID[EventKind, EventValue], lID ListenerID, priority int) bool {
 	klc := d.kindListenerContainers[evtId.Kind]
 	if klc == nil {
 		return false
 	}
-	rem := klc.remValueListener(evtId.Value, lID)
-	if klc.noListener() {
-		delete(d.kindListenerContainers, evtId.Kind)
+	lc := klc.valueListeners[evtId.Value]
+	if lc == nil {
+		return false
 	}
-	return rem
+	return lc.setPriority(lID, priority)
 }
 
 // Clear 清理状态，移除所有监听者
@@ -74,32 +320,372 @@ func (d *Dispatcher[EventKind, EventValue, ListenerID]) Clear() {
 		return
 	}
 
+	if d.globalListeners != nil {
+		d.globalListeners.clear()
+		d.globalListeners = nil
+	}
+	if d.multiKindListeners != nil {
+		d.multiKindListeners.clear()
+		d.multiKindListeners = nil
+	}
+	for _, v := range d.captureListeners {
+		v.clear()
+	}
+	d.captureListeners = nil
 	for _, v := range d.kindListenerContainers {
 		v.clear()
 	}
 	d.kindListenerContainers = map[EventKind]*kindListenerContainer[EventKind, EventValue, ListenerID]{}
+	d.pendingAddList = nil
+	d.pacedListeners = nil
+	d.contextListeners = nil
+	d.contextSweepLen = 0
+	d.index = newListenerIndex[EventKind, EventValue, ListenerID]()
 }
 
-// Dispatch 构造事件，派发给 evtID 指定的监听者们
-func (d *Dispatcher[EventKind, EventValue, ListenerID]) Dispatch(evtId EventID[EventKind, EventValue], generator interface{}, param ...interface{}) error {
-	klc := d.kindListenerContainers[evtId.Kind]
-	if klc == nil {
+// Tick 按照添加顺序，向通过 WithDebounce、WithThrottle、WithCoalesce 节流的监听者投递已到期的缓冲事件
+// 应在游戏循环的每一帧等合适的时机调用；投递过程中再次缓冲的事件留待下次 Tick 投递
+// 与 Dispatch 相同，监听者们返回的错误按照错误处理策略处理，通过 *DispatchErrors 返回
+// 投递前先移除上下文已结束的监听者，参见 AddKindListenerContext
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) Tick() error {
+	d.remContextListeners()
+	if len(d.pacedListeners) == 0 {
 		return nil
 	}
-	d.dispatching++
+
+	// 剔除已被移除的监听者，投递过程中添加的监听者留待下次 Tick
+	listeners := make([]*listener[EventKind, EventValue, ListenerID], 0, len(d.pacedListeners))
+	for _, l := range d.pacedListeners {
+		if l.callback != nil {
+			listeners = append(listeners, l)
+		}
+	}
+	d.pacedListeners = listeners
+	if len(listeners) == 0 {
+		d.pacedListeners = nil
+		return nil
+	}
+	listeners = append([]*listener[EventKind, EventValue, ListenerID](nil), listeners...)
+
+	opts := &d.options
+	now := opts.clock.Now()
+	state := dispatchContinue
+	var errs []*DispatchError[EventKind, EventValue, ListenerID]
+	for _, l := range listeners {
+		if state != dispatchContinue {
+			break
+		}
+		seq := l.pacing.seq
+		for state == dispatchContinue && l.callback != nil && !l.pendingRem {
+			if l.expired() {
+				// 已过期，丢弃缓冲的事件，监听者在下次派发或 Tick 时被移除
+				l.pacing.clear()
+				break
+			}
+			evt, ok := l.pacing.next(now, seq)
+			if !ok {
+				break
+			}
+			err := l.deliver(evt, opts)
+			if err == errEventDropped {
+				continue
+			}
+			if isListenerError(err) {
+				errs, state = opts.onError(errs, &DispatchError[EventKind, EventValue, ListenerID]{
+					EventID:    evt.eventID,
+					Stage:      DispatchStageBuffered,
+					ListenerID: l.id,
+					Err:        err,
+				})
+			}
+			if (l.consume() || err == ErrRemAfterDispatch) && l.callback != nil && !l.pendingRem {
+				d.remListener(l.reg)
+			}
+		}
+	}
+	return newDispatchErrors(errs)
+}
+
+// remExpired 移除与 reg 对应的、已失效但尚未被移除的监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) remExpired(reg Registration[EventKind, EventValue, ListenerID]) {
+	ls := d.index.listeners[reg.ListenerID]
+	if ls == nil {
+		return
+	}
+	for elem := ls.Front(); elem != nil; elem = elem.Next() {
+		if l := elem.Value.(*listener[EventKind, EventValue, ListenerID]); l.reg == reg && !l.pendingRem && l.expired() {
+			d.remListener(reg)
+			return
+		}
+	}
+}
+
+// minContextSweepLen 添加与上下文绑定的监听者时，移除上下文已结束的监听者的最小数量阈值
+const minContextSweepLen = 16
+
+// sweepContextListeners 与上下文绑定的监听者数量达到阈值时，移除上下文已结束的监听者
+// 之后将阈值设为剩余数量的两倍，使清理的开销均摊到每次添加
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) sweepContextListeners() {
+	if d.contextListeners == nil || d.contextListeners.Len() < d.contextSweepLen {
+		return
+	}
+	d.remContextListeners()
+	d.contextSweepLen = 2 * d.contextListeners.Len()
+	if d.contextSweepLen < minContextSweepLen {
+		d.contextSweepLen = minContextSweepLen
+	}
+}
+
+// addContextListener 记录与上下文绑定的监听者，监听者被移除时随之移出
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) addContextListener(l *listener[EventKind, EventValue, ListenerID]) {
+	if d.contextListeners == nil {
+		d.contextListeners = list.New()
+	}
+	l.ctxList = d.contextListeners
+	l.ctxElem = d.contextListeners.PushBack(l)
+}
+
+// remContextListeners 移除上下文已结束的监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) remContextListeners() {
+	if d.contextListeners == nil {
+		return
+	}
+	for elem := d.contextListeners.Front(); elem != nil; {
+		// 移除监听者时其随之移出列表，需先取得下一个
+		next := elem.Next()
+		if l := elem.Value.(*listener[EventKind, EventValue, ListenerID]); !l.pendingRem && l.ctx.Err() != nil {
+			d.remListener(l.reg)
+		}
+		elem = next
+	}
+}
+
+// Dispatch 构造事件，派发给 evtID 指定的监听者们
+// 监听者们返回的错误通过 *DispatchErrors 返回
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) Dispatch(evtId EventID[EventKind, EventValue], generator interface{}, param ...interface{}) error {
+	_, err := d.DispatchCancelable(evtId, generator, param...)
+	return err
+}
+
+// DispatchCancelable 构造事件，派发给 evtID 指定的监听者们
+// 返回事件是否被监听者通过 ErrStopPropagation 取消，可用于在动作发生前由监听者否决该动作
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) DispatchCancelable(evtId EventID[EventKind, EventValue], generator interface{}, param ...interface{}) (bool, error) {
+	return d.dispatch(evtId, generator, param, &d.options)
+}
+
+// DispatchWithPolicy 构造事件，派发给 evtID 指定的监听者们
+// 本次派发使用 policy 指定的错误处理策略，返回事件是否被取消
+// policy 为 ErrorPolicyHandle 但派发器未通过 WithErrorHandler 指定错误处理器时，错误与 ErrorPolicyCollectAll 相同地返回
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) DispatchWithPolicy(policy ErrorPolicy, evtId EventID[EventKind, EventValue], generator interface{}, param ...interface{}) (bool, error) {
+	opts := d.options
+	opts.errorPolicy = policy
+	return d.dispatch(evtId, generator, param, &opts)
+}
+
+// dispatch 构造事件，按照 opts 派发给 evtID 指定的监听者们
+// 存在父派发器或捕获监听者时，事件依次经过捕获阶段、目标阶段及冒泡阶段
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) dispatch(evtId EventID[EventKind, EventValue], generator interface{}, param []interface{}, opts *dispatcherOptions[EventKind, EventValue, ListenerID]) (bool, error) {
 	evt := Event[EventKind, EventValue]{
 		eventID:   evtId,
 		generator: generator,
+		current:   evtId.Kind,
 	}
 	if len(param) > 0 {
 		evt.param = param[0]
 	}
-	err := klc.dispatch(evt)
-	if klc.noListener() {
-		delete(d.kindListenerContainers, evtId.Kind)
+
+	var errs []*DispatchError[EventKind, EventValue, ListenerID]
+	if d.parent == nil && d.captureListeners == nil {
+		// 没有派发链，只有目标阶段
+		state, errs := d.dispatchLocal(evt, opts, errs)
+		return state == dispatchStopped, newDispatchErrors(errs)
 	}
+
+	evt.stopped = new(bool)
+	path := d.propagationPath()
+	// 整个派发过程中，路径上的派发器都处于派发状态，添加的监听者在最外层的派发完成后才添加，不会接收到本次事件
+	for _, p := range path {
+		p.dispatching++
+	}
+	defer func() {
+		for _, p := range path {
+			p.endDispatch()
+		}
+	}()
+	state := dispatchContinue
+
+	evt.phase = EventPhaseCapture
+	for i := len(path) - 1; i >= 0 && state == dispatchContinue && !evt.bubblingStopped(); i-- {
+		state, errs = path[i].dispatchCapture(evt, opts, errs)
+	}
+
+	if state == dispatchContinue && !evt.bubblingStopped() {
+		evt.phase = EventPhaseTarget
+		state, errs = d.dispatchLocal(evt, opts, errs)
+	}
+
+	evt.phase = EventPhaseBubble
+	for i := 1; i < len(path) && state == dispatchContinue && !evt.bubblingStopped(); i++ {
+		state, errs = path[i].dispatchLocal(evt, opts, errs)
+	}
+
+	return state == dispatchStopped, newDispatchErrors(errs)
+}
+
+// dispatchLocal 将事件派发给本派发器的监听者们
+// 依次派发给全局监听者、多类型监听者、类型监听者、值类型监听者及监听祖先类型的监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) dispatchLocal(evt Event[EventKind, EventValue], opts *dispatcherOptions[EventKind, EventValue, ListenerID], errs []*DispatchError[EventKind, EventValue, ListenerID]) (dispatchState, []*DispatchError[EventKind, EventValue, ListenerID]) {
+	klc := d.kindListenerContainers[evt.eventID.Kind]
+	if klc == nil && d.globalListeners == nil && d.multiKindListeners == nil && opts.kindParent == nil {
+		return dispatchContinue, errs
+	}
+	d.dispatching++
+	defer d.endDispatch()
+
+	state := dispatchContinue
+	if d.globalListeners != nil {
+		state, errs = dispatchContainer(&d.globalListeners, evt, DispatchStageGlobal, opts, errs)
+	}
+	if d.multiKindListeners != nil && state == dispatchContinue {
+		state, errs = dispatchContainer(&d.multiKindListeners, evt, DispatchStageMultiKind, opts, errs)
+	}
+	if klc != nil && state == dispatchContinue {
+		state, errs = klc.dispatch(evt, opts, errs)
+		if klc.noListener() {
+			delete(d.kindListenerContainers, evt.eventID.Kind)
+		}
+	}
+	if opts.kindParent != nil && state == dispatchContinue {
+		state, errs = d.dispatchAncestors(evt, opts, errs)
+	}
+	return state, errs
+}
+
+// dispatchAncestors 将事件依次冒泡派发给监听各级祖先类型的多类型监听者及类型监听者
+// 父类型解析出现环时，在回到已派发过的类型前停止
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) dispatchAncestors(evt Event[EventKind, EventValue], opts *dispatcherOptions[EventKind, EventValue, ListenerID], errs []*DispatchError[EventKind, EventValue, ListenerID]) (dispatchState, []*DispatchError[EventKind, EventValue, ListenerID]) {
+	state := dispatchContinue
+	visited := []EventKind{evt.eventID.Kind}
+	kind := evt.eventID.Kind
+	for state == dispatchContinue {
+		parent, ok := opts.kindParent(kind)
+		if !ok || containsKind(visited, parent) {
+			break
+		}
+		evt.bubbled = visited
+		visited = append(visited, parent)
+		kind = parent
+		evt.current = kind
+
+		if d.multiKindListeners != nil {
+			state, errs = dispatchContainer(&d.multiKindListeners, evt, DispatchStageAncestor, opts, errs)
+			if state != dispatchContinue {
+				break
+			}
+		}
+		klc := d.kindListenerContainers[kind]
+		if klc == nil {
+			continue
+		}
+		state, errs = klc.dispatchKind(evt, DispatchStageAncestor, opts, errs)
+		if klc.noListener() {
+			delete(d.kindListenerContainers, kind)
+		}
+	}
+	return state, errs
+}
+
+// containsKind 返回 kinds 中是否包含 kind
+func containsKind[EventKind comparable](kinds []EventKind, kind EventKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// endDispatch 结束派发，最外层派发结束时，添加挂起的监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) endDispatch() {
 	d.dispatching--
-	return err
+	if d.dispatching == 0 && d.pendingAddList != nil {
+		d.applyPendingAdds()
+	}
+}
+
+// pendingAddListener 挂起添加监听者，等待最外层的派发完成后添加
+// 不能重复添加相同ID的监听者，已挂起移除的监听者除外
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) pendingAddListener(l *listener[EventKind, EventValue, ListenerID]) bool {
+	reg := l.reg
+	if d.hasListener(reg) {
+		return false
+	}
+	if d.findPendingAdd(reg) != nil {
+		return false
+	}
+	if d.pendingAddList == nil {
+		d.pendingAddList = list.New()
+	}
+	d.pendingAddList.PushBack(l)
+	return true
+}
+
+// hasListener 返回是否存在注册信息对应的未挂起移除的监听者，不包括挂起添加的监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) hasListener(reg Registration[EventKind, EventValue, ListenerID]) bool {
+	switch reg.Scope {
+	case ListenerScopeCapture:
+		lc := d.captureListeners[reg.EventID.Kind]
+		return lc != nil && lc.hasListener(reg.ListenerID)
+	case ListenerScopeGlobal:
+		return d.globalListeners != nil && d.globalListeners.hasListener(reg.ListenerID)
+	case ListenerScopeMultiKind:
+		return d.multiKindListeners != nil && d.multiKindListeners.hasListener(reg.ListenerID)
+	}
+	klc := d.kindListenerContainers[reg.EventID.Kind]
+	if klc == nil {
+		return false
+	}
+	if reg.Scope == ListenerScopeValue {
+		return klc.hasValueListener(reg.EventID.Value, reg.ListenerID)
+	}
+	return klc.hasKindListener(reg.ListenerID)
+}
+
+// findPendingAdd 查找挂起添加的监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) findPendingAdd(reg Registration[EventKind, EventValue, ListenerID]) *list.Element {
+	if d.pendingAddList == nil {
+		return nil
+	}
+	for elem := d.pendingAddList.Front(); elem != nil; elem = elem.Next() {
+		if elem.Value.(*listener[EventKind, EventValue, ListenerID]).reg == reg {
+			return elem
+		}
+	}
+	return nil
+}
+
+// remPendingAdd 取消挂起添加的监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) remPendingAdd(reg Registration[EventKind, EventValue, ListenerID]) bool {
+	elem := d.findPendingAdd(reg)
+	if elem == nil {
+		return false
+	}
+	d.pendingAddList.Remove(elem).(*listener[EventKind, EventValue, ListenerID]).reset()
+	return true
+}
+
+// applyPendingAdds 按挂起的顺序添加监听者
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) applyPendingAdds() {
+	pendingAddList := d.pendingAddList
+	d.pendingAddList = nil
+	for elem := pendingAddList.Front(); elem != nil; elem = elem.Next() {
+		l := elem.Value.(*listener[EventKind, EventValue, ListenerID])
+		if !d.directAddListener(l) {
+			l.reset()
+		} else {
+			d.replaySticky(l)
+		}
+	}
 }
 
 func (d *Dispatcher[EventKind, EventValue, ListenerID]) addORGetKindListeners(evtKind EventKind) *kindListenerContainer[EventKind, EventValue, ListenerID] {
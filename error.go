@@ -3,35 +3,86 @@ package gevent
 import (
 	"errors"
 	"fmt"
+	"runtime/debug"
 	"strings"
 )
 
-// dispatchError 用于封装派发事件时监听者返回的错误
-// 记录派发的类别、派发时的事件ID
-type dispatchError[EventKind, EventValue comparable] struct {
-	dt      string                         // 派发的类别
-	eventID EventID[EventKind, EventValue] // 事件ID
-	err     error                          // error
+// ErrorPolicy 错误处理策略，决定派发事件时如何处理监听者返回的错误
+type ErrorPolicy int
+
+const (
+	ErrorPolicyCollectAll ErrorPolicy = iota // 派发给所有监听者，收集所有错误后返回，默认策略
+	ErrorPolicyFailFast                      // 遇到第一个错误即停止派发，返回该错误
+	ErrorPolicyHandle                        // 派发给所有监听者，每个错误交由 ErrorHandler 处理，派发本身不返回错误；未指定 ErrorHandler 时与 ErrorPolicyCollectAll 相同
+)
+
+// ErrorHandler 错误处理器
+// 错误处理策略为 ErrorPolicyHandle 时，接收监听者返回的每一个错误
+type ErrorHandler[EventKind, EventValue, ListenerID comparable] func(evtId EventID[EventKind, EventValue], lID ListenerID, err error)
+
+// EventErrorHandler 事件错误处理器
+// 由 AsyncDispatcher、DeferredDispatcher、Scheduler 等在事件离开调用方后才派发的组件使用，
+// 接收派发事件时返回的错误，通常为 *DispatchErrors
+type EventErrorHandler[EventKind, EventValue comparable] func(evtId EventID[EventKind, EventValue], err error)
+
+// DispatchStage 派发阶段，标识监听者是以何种方式接收到事件的
+type DispatchStage int
+
+const (
+	DispatchStageKind      DispatchStage = iota // 派发给类型事件监听者
+	DispatchStageValue                          // 派发给值类事件监听者
+	DispatchStageGlobal                         // 派发给全局监听者
+	DispatchStageMultiKind                      // 派发给多类型监听者
+	DispatchStageAncestor                       // 冒泡派发给监听祖先类型的多类型监听者或类型事件监听者
+	DispatchStageCapture                        // 捕获阶段派发给捕获监听者
+	DispatchStageBuffered                       // 由 Tick 投递缓冲的事件
+)
+
+func (s DispatchStage) String() string {
+	switch s {
+	case DispatchStageKind:
+		return "kind"
+	case DispatchStageValue:
+		return "value"
+	case DispatchStageGlobal:
+		return "global"
+	case DispatchStageMultiKind:
+		return "multi-kind"
+	case DispatchStageAncestor:
+		return "ancestor"
+	case DispatchStageCapture:
+		return "capture"
+	case DispatchStageBuffered:
+		return "buffered"
+	default:
+		return fmt.Sprintf("DispatchStage(%d)", int(s))
+	}
 }
 
-func (e *dispatchError[EventKind, EventValue]) Error() string {
-	return fmt.Sprintf("dispatch %s event of id={kind:%v, value:%v}: %v", e.dt, e.eventID.Value, e.eventID.Value, e.err.Error())
+// DispatchError 派发事件时单个监听者返回的错误
+// 记录派发的事件ID、派发阶段以及返回错误的监听者
+type DispatchError[EventKind, EventValue, ListenerID comparable] struct {
+	EventID    EventID[EventKind, EventValue] // 事件ID
+	Stage      DispatchStage                  // 派发阶段
+	ListenerID ListenerID                     // 监听者ID
+	Err        error                          // 监听者返回的错误
 }
 
-func (e *dispatchError[EventKind, EventValue]) Is(o error) bool {
-	return errors.Is(e.err, o)
+func (e *DispatchError[EventKind, EventValue, ListenerID]) Error() string {
+	return fmt.Sprintf("dispatch %s event of id={kind:%v, value:%v} to listener %v: %v", e.Stage, e.EventID.Kind, e.EventID.Value, e.ListenerID, e.Err.Error())
 }
 
-func (e *dispatchError[EventKind, EventValue]) As(o interface{}) bool {
-	return errors.As(e.err, o)
+func (e *DispatchError[EventKind, EventValue, ListenerID]) Unwrap() error {
+	return e.Err
 }
 
-// dispatchErrors 用于统计派发事件时监听者们返回的所有错误
-type dispatchErrors struct {
-	errors []error
+// DispatchErrors 派发事件时监听者们返回的所有错误
+// 按照监听者接收事件的顺序排列
+type DispatchErrors[EventKind, EventValue, ListenerID comparable] struct {
+	errors []*DispatchError[EventKind, EventValue, ListenerID]
 }
 
-func (e *dispatchErrors) Error() string {
+func (e *DispatchErrors[EventKind, EventValue, ListenerID]) Error() string {
 	sb := strings.Builder{}
 	sb.WriteString("[")
 	for i, err := range e.errors {
@@ -44,7 +95,35 @@ func (e *dispatchErrors) Error() string {
 	return sb.String()
 }
 
-func (e *dispatchErrors) Is(o error) bool {
+// Len 返回错误数量
+func (e *DispatchErrors[EventKind, EventValue, ListenerID]) Len() int {
+	return len(e.errors)
+}
+
+// Errors 返回所有监听者的错误，返回值不可修改
+func (e *DispatchErrors[EventKind, EventValue, ListenerID]) Errors() []*DispatchError[EventKind, EventValue, ListenerID] {
+	return e.errors
+}
+
+// Range 按顺序遍历所有监听者的错误，f 返回 false 时停止遍历
+func (e *DispatchErrors[EventKind, EventValue, ListenerID]) Range(f func(*DispatchError[EventKind, EventValue, ListenerID]) bool) {
+	for _, err := range e.errors {
+		if !f(err) {
+			return
+		}
+	}
+}
+
+// Unwrap 返回所有监听者的错误，支持 Go 1.20 及以上版本的 errors.Is/As
+func (e *DispatchErrors[EventKind, EventValue, ListenerID]) Unwrap() []error {
+	errs := make([]error, len(e.errors))
+	for i, err := range e.errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+func (e *DispatchErrors[EventKind, EventValue, ListenerID]) Is(o error) bool {
 	for _, err := range e.errors {
 		if errors.Is(err, o) {
 			return true
@@ -53,7 +132,7 @@ func (e *dispatchErrors) Is(o error) bool {
 	return false
 }
 
-func (e *dispatchErrors) As(o interface{}) bool {
+func (e *DispatchErrors[EventKind, EventValue, ListenerID]) As(o interface{}) bool {
 	for _, err := range e.errors {
 		if errors.As(err, o) {
 			return true
@@ -61,3 +140,39 @@ func (e *dispatchErrors) As(o interface{}) bool {
 	}
 	return false
 }
+
+// newDispatchErrors 若存在错误，构造 *DispatchErrors
+func newDispatchErrors[EventKind, EventValue, ListenerID comparable](errs []*DispatchError[EventKind, EventValue, ListenerID]) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return &DispatchErrors[EventKind, EventValue, ListenerID]{errors: errs}
+}
+
+// ListenerPanicError 监听者回调 panic 时产生的错误
+// 仅在派发器开启 WithRecoverPanic 时产生
+type ListenerPanicError[EventKind, EventValue, ListenerID comparable] struct {
+	ListenerID ListenerID                     // 监听者ID
+	EventID    EventID[EventKind, EventValue] // 事件ID
+	Value      interface{}                    // panic 的值
+	Stack      []byte                         // panic 时的调用栈
+}
+
+func newListenerPanicError[EventKind, EventValue, ListenerID comparable](lID ListenerID, evtId EventID[EventKind, EventValue], value interface{}) *ListenerPanicError[EventKind, EventValue, ListenerID] {
+	return &ListenerPanicError[EventKind, EventValue, ListenerID]{
+		ListenerID: lID,
+		EventID:    evtId,
+		Value:      value,
+		Stack:      debug.Stack(),
+	}
+}
+
+func (e *ListenerPanicError[EventKind, EventValue, ListenerID]) Error() string {
+	return fmt.Sprintf("listener %v panic on event of id={kind:%v, value:%v}: %v", e.ListenerID, e.EventID.Kind, e.EventID.Value, e.Value)
+}
+
+// Unwrap 若 panic 的值为 error，返回该 error
+func (e *ListenerPanicError[EventKind, EventValue, ListenerID]) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
@@ -1,5 +1,7 @@
 package gevent
 
+import "fmt"
+
 // EventID 事件ID，用于唯一标识一个事
 // EventKind 事件类型
 // EventValue 事件值
@@ -8,11 +10,39 @@ type EventID[EventKind, EventValue comparable] struct {
 	Value EventValue
 }
 
+// EventPhase 事件在派发链中所处的阶段
+type EventPhase int
+
+const (
+	EventPhaseTarget  EventPhase = iota // 目标阶段，派发给发起派发的派发器的监听者
+	EventPhaseCapture                   // 捕获阶段，自根派发器向下派发给各派发器的捕获监听者
+	EventPhaseBubble                    // 冒泡阶段，自父派发器向上派发给各祖先派发器的监听者
+)
+
+func (p EventPhase) String() string {
+	switch p {
+	case EventPhaseTarget:
+		return "target"
+	case EventPhaseCapture:
+		return "capture"
+	case EventPhaseBubble:
+		return "bubble"
+	default:
+		return fmt.Sprintf("EventPhase(%d)", int(p))
+	}
+}
+
 // Event 产生的事件
 type Event[EventKind, EventValue comparable] struct {
 	eventID   EventID[EventKind, EventValue] // 事件ID
 	param     interface{}                    // 参数
 	generator interface{}                    // The generator of the event
+	current   EventKind                      // 当前派发的事件类型，向祖先类型冒泡时为祖先类型
+	phase     EventPhase                     // 当前所处的阶段
+	stopped   *bool                          // 是否已停止冒泡，在派发链中共享，为空时无须记录
+	payloads  []interface{}                  // 合并投递的所有参数，仅合并投递的事件有效
+	replayed  bool                           // 是否为添加监听者时回放的粘性事件
+	bubbled   []EventKind                    // 向祖先类型冒泡时，已经派发过的事件类型
 }
 
 func (e *Event[EventKind, EventValue]) EventID() EventID[EventKind, EventValue] { return e.eventID }
@@ -20,3 +50,34 @@ func (e *Event[EventKind, EventValue]) EventID() EventID[EventKind, EventValue]
 func (e *Event[EventKind, EventValue]) Param() interface{} { return e.param }
 
 func (e *Event[EventKind, EventValue]) Generator() interface{} { return e.generator }
+
+// CurrentKind 返回监听者是通过哪个事件类型接收到事件的
+// 向祖先类型冒泡时为该祖先类型，否则与 EventID().Kind 相同
+func (e *Event[EventKind, EventValue]) CurrentKind() EventKind { return e.current }
+
+// Phase 返回事件在派发链中所处的阶段
+func (e *Event[EventKind, EventValue]) Phase() EventPhase { return e.phase }
+
+// Payloads 返回事件的所有参数，按照事件产生的顺序排列
+// 通过 WithCoalesce 合并投递的事件返回被合并的所有事件的参数，其它事件返回仅包含 Param 的切片
+func (e *Event[EventKind, EventValue]) Payloads() []interface{} {
+	if e.payloads != nil {
+		return e.payloads
+	}
+	return []interface{}{e.param}
+}
+
+// Replayed 返回事件是否为添加监听者时回放的粘性事件
+func (e *Event[EventKind, EventValue]) Replayed() bool { return e.replayed }
+
+// stopBubbling 停止冒泡
+func (e *Event[EventKind, EventValue]) stopBubbling() {
+	if e.stopped != nil {
+		*e.stopped = true
+	}
+}
+
+// bubblingStopped 返回是否已停止冒泡
+func (e *Event[EventKind, EventValue]) bubblingStopped() bool {
+	return e.stopped != nil && *e.stopped
+}
@@ -1,8 +1,12 @@
 package gevent
 
 import (
+	"context"
+	"errors"
 	"math/rand"
+	"sync"
 	"testing"
+	"time"
 )
 
 type testET int
@@ -12,6 +16,28 @@ type testEventID = EventID[testET, testEV]
 type testEvent = Event[testET, testEV]
 type testListenerCallback = ListenerCallback[testET, testEV]
 
+// testClock 可控的时钟
+type testClock struct {
+	mtx sync.Mutex
+	now time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Unix(0, 0)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.now = c.now.Add(d)
+}
+
 func TestTypeHandler(t *testing.T) {
 	dispatcher := NewDispatcher[testET, testEV, testLID]()
 	eventType := testET(1)
@@ -31,8 +57,8 @@ func TestTypeHandler(t *testing.T) {
 		return nil
 	}
 
-	dispatcher.AddKindListener(eventType, key1, callback1, false)
-	dispatcher.AddKindListener(eventType, key2, callback2, false)
+	dispatcher.AddKindListener(eventType, key1, callback1)
+	dispatcher.AddKindListener(eventType, key2, callback2)
 	if err := dispatcher.Dispatch(testEventID{eventType, 1}, nil, nil); err != nil {
 		t.Fatal("there must no error")
 	}
@@ -62,7 +88,7 @@ func TestTypeHandler(t *testing.T) {
 	}
 
 	value = 0
-	dispatcher.AddKindListener(eventType, key2, callback2, true)
+	dispatcher.AddKindListener(eventType, key2, callback2, WithOnce())
 	if err := dispatcher.Dispatch(testEventID{eventType, 1}, nil, nil); err != nil {
 		t.Fatal("there must no error")
 	}
@@ -97,8 +123,8 @@ func TestHandler(t *testing.T) {
 		return nil
 	}
 
-	dispatcher.AddValueListener(testEventID{eventType1, eventVal1}, key1, handler1, false)
-	dispatcher.AddValueListener(testEventID{eventType2, eventVal2}, key2, handler2, false)
+	dispatcher.AddValueListener(testEventID{eventType1, eventVal1}, key1, handler1)
+	dispatcher.AddValueListener(testEventID{eventType2, eventVal2}, key2, handler2)
 	if err := dispatcher.Dispatch(testEventID{eventType1, eventVal1}, nil, nil); err != nil {
 		t.Fatal("there must no error")
 	}
@@ -129,7 +155,7 @@ func TestHandler(t *testing.T) {
 	}
 
 	value = 0
-	dispatcher.AddKindListener(eventType1, key1, handler1, false)
+	dispatcher.AddKindListener(eventType1, key1, handler1)
 	if err := dispatcher.Dispatch(testEventID{eventType1, eventVal1}, nil, nil); err != nil {
 		t.Fatal("there must no error")
 	}
@@ -146,7 +172,7 @@ func TestHandler(t *testing.T) {
 	}
 
 	value = 0
-	dispatcher.AddKindListener(eventType2, key2, handler2, false)
+	dispatcher.AddKindListener(eventType2, key2, handler2)
 	if err := dispatcher.Dispatch(testEventID{eventType2, eventVal2}, nil, nil); err != nil {
 		t.Fatal("there must no error")
 	}
@@ -178,6 +204,853 @@ func TestHandler(t *testing.T) {
 	}
 }
 
+func TestListenerPriority(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+	var order []testLID
+	newCallback := func(lID testLID) testListenerCallback {
+		return func(e testEvent) error {
+			order = append(order, lID)
+			return nil
+		}
+	}
+	checkOrder := func(expected ...testLID) {
+		t.Helper()
+		order = order[:0]
+		if err := dispatcher.Dispatch(testEventID{eventType, 1}, nil); err != nil {
+			t.Fatal("there must no error")
+		}
+		if len(order) != len(expected) {
+			t.Fatal("order must be", expected, "got", order)
+		}
+		for i := range expected {
+			if order[i] != expected[i] {
+				t.Fatal("order must be", expected, "got", order)
+			}
+		}
+	}
+
+	dispatcher.AddKindListener(eventType, 1, newCallback(1))
+	dispatcher.AddKindListener(eventType, 2, newCallback(2), WithPriority(10))
+	dispatcher.AddKindListener(eventType, 3, newCallback(3))
+	dispatcher.AddKindListener(eventType, 4, newCallback(4), WithPriority(-1))
+	dispatcher.AddKindListener(eventType, 5, newCallback(5), WithPriority(10))
+	checkOrder(2, 5, 1, 3, 4)
+
+	if !dispatcher.SetKindListenerPriority(eventType, 4, 20) {
+		t.Fatal("set priority must succeed")
+	}
+	checkOrder(4, 2, 5, 1, 3)
+
+	if !dispatcher.SetKindListenerPriority(eventType, 2, 10) {
+		t.Fatal("set priority must succeed")
+	}
+	checkOrder(4, 5, 2, 1, 3)
+
+	// 派发过程中修改优先级，派发完成后生效
+	dispatcher.AddKindListener(eventType, 6, func(e testEvent) error {
+		order = append(order, 6)
+		dispatcher.SetKindListenerPriority(eventType, 6, 100)
+		return nil
+	}, WithPriority(-10))
+	checkOrder(4, 5, 2, 1, 3, 6)
+	checkOrder(6, 4, 5, 2, 1, 3)
+
+	if dispatcher.SetKindListenerPriority(eventType, 7, 0) {
+		t.Fatal("set priority of absent listener must fail")
+	}
+}
+
+func TestStopPropagation(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+	eventVal := testEV(1)
+	value := 0
+	veto := true
+
+	dispatcher.AddKindListener(eventType, 1, func(e testEvent) error {
+		value += 1
+		return nil
+	})
+	dispatcher.AddKindListener(eventType, 2, func(e testEvent) error {
+		if veto {
+			return ErrStopPropagation
+		}
+		return nil
+	})
+	dispatcher.AddKindListener(eventType, 3, func(e testEvent) error {
+		value += 10
+		return nil
+	})
+	dispatcher.AddValueListener(testEventID{eventType, eventVal}, 4, func(e testEvent) error {
+		value += 100
+		return nil
+	})
+
+	cancelled, err := dispatcher.DispatchCancelable(testEventID{eventType, eventVal}, nil)
+	if err != nil {
+		t.Fatal("there must no error")
+	}
+	if !cancelled {
+		t.Fatal("event must be cancelled")
+	}
+	if value != 1 {
+		t.Fatal("value must be", 1)
+	}
+
+	value = 0
+	veto = false
+	cancelled, err = dispatcher.DispatchCancelable(testEventID{eventType, eventVal}, nil)
+	if err != nil {
+		t.Fatal("there must no error")
+	}
+	if cancelled {
+		t.Fatal("event must not be cancelled")
+	}
+	if value != 111 {
+		t.Fatal("value must be", 111)
+	}
+}
+
+func TestAddListenerOnDispatching(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+	eventVal := testEV(1)
+	value := 0
+
+	add := func(e testEvent) error {
+		value += 1
+		return nil
+	}
+	dispatcher.AddKindListener(eventType, 1, func(e testEvent) error {
+		// 派发过程中添加的监听者，不会接收正在派发的事件
+		if _, ok := dispatcher.AddKindListener(eventType, 2, add); !ok {
+			t.Fatal("add listener on dispatching must succeed")
+		}
+		if _, ok := dispatcher.AddKindListener(eventType, 2, add); ok {
+			t.Fatal("repeated pending listener must not be added")
+		}
+		if _, ok := dispatcher.AddKindListener(eventType, 1, add); ok {
+			t.Fatal("repeated listener must not be added")
+		}
+		dispatcher.AddValueListener(testEventID{eventType, eventVal}, 3, add)
+		dispatcher.AddValueListener(testEventID{eventType, eventVal}, 4, add)
+		dispatcher.RemValueListener(testEventID{eventType, eventVal}, 4)
+		// 嵌套派发的事件也不会被挂起添加的监听者接收
+		dispatcher.Dispatch(testEventID{eventType + 1, eventVal}, nil)
+		return ErrRemAfterDispatch
+	})
+	dispatcher.AddKindListener(eventType+1, 1, func(e testEvent) error {
+		dispatcher.AddKindListener(eventType+1, 2, add)
+		return nil
+	})
+
+	if err := dispatcher.Dispatch(testEventID{eventType, eventVal}, nil); err != nil {
+		t.Fatal("there must no error")
+	}
+	if value != 0 {
+		t.Fatal("value must be", 0)
+	}
+
+	if err := dispatcher.Dispatch(testEventID{eventType, eventVal}, nil); err != nil {
+		t.Fatal("there must no error")
+	}
+	if value != 2 {
+		t.Fatal("value must be", 2)
+	}
+
+	if err := dispatcher.Dispatch(testEventID{eventType + 1, eventVal}, nil); err != nil {
+		t.Fatal("there must no error")
+	}
+	if value != 3 {
+		t.Fatal("value must be", 3)
+	}
+}
+
+func TestRecoverPanic(t *testing.T) {
+	dispatcher := NewDispatcher(WithRecoverPanic[testET, testEV, testLID]())
+	eventType := testET(1)
+	value := 0
+
+	dispatcher.AddKindListener(eventType, 1, func(e testEvent) error {
+		panic("test panic")
+	})
+	dispatcher.AddKindListener(eventType, 2, func(e testEvent) error {
+		value += 1
+		return nil
+	}, WithOnce())
+
+	err := dispatcher.Dispatch(testEventID{eventType, 1}, nil)
+	var panicErr *ListenerPanicError[testET, testEV, testLID]
+	if !errors.As(err, &panicErr) {
+		t.Fatal("error must be ListenerPanicError")
+	}
+	if panicErr.ListenerID != 1 || panicErr.EventID != (testEventID{eventType, 1}) || panicErr.Value != "test panic" || len(panicErr.Stack) == 0 {
+		t.Fatal("panic error mismatch", panicErr)
+	}
+	if value != 1 {
+		t.Fatal("value must be", 1)
+	}
+	if dispatcher.dispatching != 0 {
+		t.Fatal("dispatching must be", 0)
+	}
+	if dispatcher.kindListenerContainers[eventType].kindListeners.listenerList.Len() != 1 {
+		t.Fatal("once listener must be removed")
+	}
+}
+
+func TestPanicConsistency(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+
+	dispatcher.AddKindListener(eventType, 1, func(e testEvent) error {
+		return ErrRemAfterDispatch
+	})
+	dispatcher.AddKindListener(eventType, 2, func(e testEvent) error {
+		dispatcher.AddKindListener(eventType, 3, func(e testEvent) error { return nil })
+		panic("test panic")
+	})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic must be propagated")
+			}
+		}()
+		dispatcher.Dispatch(testEventID{eventType, 1}, nil)
+	}()
+
+	// 未恢复的 panic 不能破坏派发器的状态
+	if dispatcher.dispatching != 0 {
+		t.Fatal("dispatching must be", 0)
+	}
+	klc := dispatcher.kindListenerContainers[eventType]
+	if klc.dispatching != 0 || klc.kindListeners.dispatching != 0 {
+		t.Fatal("container dispatching must be", 0)
+	}
+	if klc.hasKindListener(1) || !klc.hasKindListener(3) {
+		t.Fatal("pending operations must be applied")
+	}
+	dispatcher.Clear()
+	if len(dispatcher.kindListenerContainers) != 0 {
+		t.Fatal("handlers must clear")
+	}
+}
+
+func TestDispatchErrors(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	evtId := testEventID{1, 2}
+	testErr1 := errors.New("test error 1")
+	testErr2 := errors.New("test error 2")
+
+	dispatcher.AddKindListener(evtId.Kind, 1, func(e testEvent) error { return testErr1 })
+	dispatcher.AddKindListener(evtId.Kind, 2, func(e testEvent) error { return nil })
+	dispatcher.AddValueListener(evtId, 3, func(e testEvent) error { return testErr2 })
+
+	err := dispatcher.Dispatch(evtId, nil)
+	if !errors.Is(err, testErr1) || !errors.Is(err, testErr2) {
+		t.Fatal("error must wrap listener errors")
+	}
+	var dispatchErrs *DispatchErrors[testET, testEV, testLID]
+	if !errors.As(err, &dispatchErrs) {
+		t.Fatal("error must be DispatchErrors")
+	}
+	if dispatchErrs.Len() != 2 || len(dispatchErrs.Unwrap()) != 2 {
+		t.Fatal("error count must be", 2)
+	}
+
+	expected := []DispatchError[testET, testEV, testLID]{
+		{EventID: evtId, Stage: DispatchStageKind, ListenerID: 1, Err: testErr1},
+		{EventID: evtId, Stage: DispatchStageValue, ListenerID: 3, Err: testErr2},
+	}
+	i := 0
+	dispatchErrs.Range(func(e *DispatchError[testET, testEV, testLID]) bool {
+		if *e != expected[i] {
+			t.Fatal("dispatch error mismatch", e)
+		}
+		i++
+		return true
+	})
+	if i != len(expected) {
+		t.Fatal("range count must be", len(expected))
+	}
+
+	var dispatchErr *DispatchError[testET, testEV, testLID]
+	if !errors.As(err, &dispatchErr) || dispatchErr.ListenerID != 1 {
+		t.Fatal("first dispatch error must be from listener 1")
+	}
+	if dispatchErr.Error() != "dispatch kind event of id={kind:1, value:2} to listener 1: test error 1" {
+		t.Fatal("unexpected error message", dispatchErr.Error())
+	}
+}
+
+func TestErrorPolicy(t *testing.T) {
+	evtId := testEventID{1, 1}
+	testErr := errors.New("test error")
+	var handled []testLID
+	dispatcher := NewDispatcher(WithErrorHandler(func(id testEventID, lID testLID, err error) {
+		if id != evtId || err != testErr {
+			t.Fatal("handled error mismatch")
+		}
+		handled = append(handled, lID)
+	}))
+	value := 0
+
+	dispatcher.AddKindListener(evtId.Kind, 1, func(e testEvent) error { return testErr })
+	dispatcher.AddKindListener(evtId.Kind, 2, func(e testEvent) error {
+		value += 1
+		return testErr
+	})
+	dispatcher.AddValueListener(evtId, 3, func(e testEvent) error {
+		value += 10
+		return nil
+	})
+
+	// 默认策略：交由错误处理器处理
+	if err := dispatcher.Dispatch(evtId, nil); err != nil {
+		t.Fatal("there must no error")
+	}
+	if len(handled) != 2 || handled[0] != 1 || handled[1] != 2 {
+		t.Fatal("handled listeners must be [1 2], got", handled)
+	}
+	if value != 11 {
+		t.Fatal("value must be", 11)
+	}
+
+	// 收集所有错误
+	value = 0
+	_, err := dispatcher.DispatchWithPolicy(ErrorPolicyCollectAll, evtId, nil)
+	var dispatchErrs *DispatchErrors[testET, testEV, testLID]
+	if !errors.As(err, &dispatchErrs) || dispatchErrs.Len() != 2 {
+		t.Fatal("error count must be", 2)
+	}
+	if value != 11 {
+		t.Fatal("value must be", 11)
+	}
+
+	// 遇到第一个错误即停止
+	value = 0
+	cancelled, err := dispatcher.DispatchWithPolicy(ErrorPolicyFailFast, evtId, nil)
+	if cancelled {
+		t.Fatal("event must not be cancelled")
+	}
+	if !errors.As(err, &dispatchErrs) || dispatchErrs.Len() != 1 || dispatchErrs.Errors()[0].ListenerID != 1 {
+		t.Fatal("error must be from listener 1")
+	}
+	if value != 0 {
+		t.Fatal("value must be", 0)
+	}
+}
+
+func TestErrorPolicyHandleWithoutHandler(t *testing.T) {
+	testErr := errors.New("test error")
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	concurrent := NewConcurrentDispatcher[testET, testEV, testLID]()
+	callback := func(e testEvent) error {
+		return testErr
+	}
+	dispatcher.AddKindListener(1, 1, callback)
+	dispatcher.AddKindListener(1, 2, callback)
+	concurrent.AddKindListener(1, 1, callback)
+
+	// 未指定错误处理器时，错误不会被丢弃
+	var dispatchErrs *DispatchErrors[testET, testEV, testLID]
+	_, err := dispatcher.DispatchWithPolicy(ErrorPolicyHandle, testEventID{1, 1}, nil)
+	if !errors.As(err, &dispatchErrs) || dispatchErrs.Len() != 2 || !errors.Is(err, testErr) {
+		t.Fatal("error count must be", 2)
+	}
+	if _, err := concurrent.DispatchWithPolicy(ErrorPolicyHandle, testEventID{1, 1}, nil); !errors.Is(err, testErr) {
+		t.Fatal("error must be test error")
+	}
+
+	dispatcher = NewDispatcher(WithErrorPolicy[testET, testEV, testLID](ErrorPolicyHandle))
+	dispatcher.AddKindListener(1, 1, callback)
+	if err := dispatcher.Dispatch(testEventID{1, 1}, nil); !errors.Is(err, testErr) {
+		t.Fatal("error must be test error")
+	}
+}
+
+func TestRemListener(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	value := 0
+
+	add := func(e testEvent) error {
+		value += 1
+		return nil
+	}
+	dispatcher.AddKindListener(1, 1, add)
+	dispatcher.AddValueListener(testEventID{1, 1}, 1, add)
+	dispatcher.AddValueListener(testEventID{2, 1}, 1, add)
+	dispatcher.AddKindListener(2, 2, add)
+
+	regs := dispatcher.ListenersOf(1)
+	if len(regs) != 3 {
+		t.Fatal("registration count must be", 3)
+	}
+	if regs[0].Scope != ListenerScopeKind || regs[1].EventID != (testEventID{1, 1}) || regs[2].EventID != (testEventID{2, 1}) {
+		t.Fatal("registrations must be in add order", regs)
+	}
+
+	if !dispatcher.RemListener(1) {
+		t.Fatal("listener 1 must be removed")
+	}
+	if dispatcher.RemListener(1) {
+		t.Fatal("listener 1 must not be removed again")
+	}
+	if len(dispatcher.ListenersOf(1)) != 0 {
+		t.Fatal("listener 1 must have no registration")
+	}
+	dispatcher.Dispatch(testEventID{1, 1}, nil)
+	dispatcher.Dispatch(testEventID{2, 1}, nil)
+	if value != 1 {
+		t.Fatal("value must be", 1)
+	}
+	if _, ok := dispatcher.kindListenerContainers[1]; ok {
+		t.Fatal("kind listener container must be removed")
+	}
+
+	// 派发过程中移除，遵循挂起移除的语义
+	value = 0
+	dispatcher.AddKindListener(2, 3, func(e testEvent) error {
+		dispatcher.AddValueListener(testEventID{2, 1}, 2, add)
+		if !dispatcher.RemListener(2) {
+			t.Fatal("listener 2 must be removed")
+		}
+		if len(dispatcher.ListenersOf(2)) != 0 {
+			t.Fatal("listener 2 must have no registration")
+		}
+		return nil
+	}, WithPriority(1))
+	dispatcher.Dispatch(testEventID{2, 1}, nil)
+	if value != 0 {
+		t.Fatal("value must be", 0)
+	}
+	if len(dispatcher.ListenersOf(2)) != 0 {
+		t.Fatal("listener 2 must have no registration")
+	}
+	if len(dispatcher.index.listeners) != 1 {
+		t.Fatal("index must only contain listener 3")
+	}
+
+	dispatcher.Clear()
+	if len(dispatcher.ListenersOf(3)) != 0 {
+		t.Fatal("listener 3 must have no registration")
+	}
+}
+
+func TestListenerLifetime(t *testing.T) {
+	clock := newTestClock()
+	dispatcher := NewDispatcher(WithClock[testET, testEV, testLID](clock))
+	eventType := testET(1)
+	counts := map[testLID]int{}
+
+	count := func(lID testLID) testListenerCallback {
+		return func(e testEvent) error {
+			counts[lID]++
+			return nil
+		}
+	}
+	dispatcher.AddKindListener(eventType, 1, count(1), WithTimes(3))
+	ttlSub, _ := dispatcher.AddKindListener(eventType, 2, count(2), WithTTL(2*time.Second))
+	dispatcher.AddKindListener(eventType, 3, count(3), WithDeadline(clock.Now().Add(time.Second)))
+	// 同时指定时以较早的过期时间为准
+	dispatcher.AddKindListener(eventType, 4, count(4), WithTTL(time.Second), WithDeadline(clock.Now().Add(time.Hour)))
+	dispatcher.AddKindListener(eventType, 5, count(5), WithTimes(2), WithTTL(time.Hour))
+
+	for i := 0; i < 5; i++ {
+		dispatcher.Dispatch(testEventID{eventType, 1}, nil)
+		clock.Advance(500 * time.Millisecond)
+	}
+	if counts[1] != 3 || counts[2] != 4 || counts[3] != 2 || counts[4] != 2 || counts[5] != 2 {
+		t.Fatal("dispatch counts mismatch", counts)
+	}
+	if ttlSub.Active() {
+		t.Fatal("expired subscription must be inactive")
+	}
+
+	// 过期的监听者在派发时被移除
+	if dispatcher.kindListenerContainers[eventType] != nil {
+		t.Fatal("expired listeners must be removed")
+	}
+
+	// 过期但尚未派发的监听者不会被列出，但仍可被移除
+	dispatcher.AddValueListener(testEventID{eventType, 1}, 6, count(6), WithTTL(time.Second))
+	if len(dispatcher.ListenersOf(6)) != 1 {
+		t.Fatal("listener 6 must be registered")
+	}
+	clock.Advance(time.Second)
+	if len(dispatcher.ListenersOf(6)) != 0 {
+		t.Fatal("expired listener must not be listed")
+	}
+	if !dispatcher.RemListener(6) || dispatcher.kindListenerContainers[eventType] != nil {
+		t.Fatal("expired listener must be removed")
+	}
+}
+
+func TestContextListener(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+	value := 0
+
+	add := func(e testEvent) error {
+		value += 1
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	kindSub, _ := dispatcher.AddKindListenerContext(ctx, eventType, 2, add)
+	dispatcher.AddValueListenerContext(ctx, testEventID{eventType, 1}, 3, add)
+	dispatcher.AddKindListener(eventType, 1, func(e testEvent) error {
+		if value > 0 {
+			// 在其它 goroutine 中取消，派发中的后续监听者不再接收事件
+			done := make(chan struct{})
+			go func() {
+				cancel()
+				close(done)
+			}()
+			<-done
+		}
+		return nil
+	}, WithPriority(1))
+
+	dispatcher.Dispatch(testEventID{eventType, 1}, nil)
+	if value != 2 {
+		t.Fatal("value must be", 2)
+	}
+	dispatcher.Dispatch(testEventID{eventType, 1}, nil)
+	if value != 2 {
+		t.Fatal("value must be", 2)
+	}
+	if kindSub.Active() || len(dispatcher.ListenersOf(2)) != 0 || len(dispatcher.ListenersOf(3)) != 0 {
+		t.Fatal("context listeners must be removed")
+	}
+	if dispatcher.kindListenerContainers[eventType].valueListeners != nil {
+		t.Fatal("value listeners must be removed")
+	}
+
+	// 已结束的上下文不会添加监听者
+	if sub, ok := dispatcher.AddKindListenerContext(ctx, eventType, 4, add); ok || sub != nil {
+		t.Fatal("listener with done context must not be added")
+	}
+
+	// 不再派发事件时，由 Tick 移除上下文已结束的监听者
+	ctx, cancel = context.WithCancel(context.Background())
+	dispatcher.AddValueListenerContext(ctx, testEventID{eventType, 2}, 5, add)
+	cancel()
+	dispatcher.Tick()
+	if dispatcher.index.listeners[5] != nil || dispatcher.kindListenerContainers[eventType].valueListeners != nil {
+		t.Fatal("context listener must be removed")
+	}
+	if dispatcher.contextListeners.Len() != 0 {
+		t.Fatal("context listeners must be empty")
+	}
+}
+
+func TestContextListenerSweep(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	callback := func(e testEvent) error { return nil }
+
+	// 取消订阅的监听者随即移出上下文监听者列表
+	for i := 0; i < 1000; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		sub, _ := dispatcher.AddKindListenerContext(ctx, testET(i), 1, callback)
+		sub.Unsubscribe()
+		cancel()
+	}
+	if dispatcher.contextListeners.Len() != 0 || len(dispatcher.kindListenerContainers) != 0 {
+		t.Fatal("context listeners must be empty")
+	}
+
+	// 不派发事件也不调用 Tick 时，上下文已结束的监听者在添加其它监听者时被移除，不会无限累积
+	for i := 0; i < 1000; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		dispatcher.AddKindListenerContext(ctx, testET(i), 1, callback)
+		cancel()
+	}
+	if dispatcher.contextListeners.Len() > minContextSweepLen || len(dispatcher.kindListenerContainers) > minContextSweepLen {
+		t.Fatal("cancelled context listeners must not accumulate")
+	}
+	if len(dispatcher.ListenersOf(1)) != 0 {
+		t.Fatal("cancelled context listeners must not be listed")
+	}
+
+	// 上下文已结束的监听者不会占用监听者ID
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, ok := dispatcher.AddKindListenerContext(ctx, testET(999), 1, callback); !ok {
+		t.Fatal("listener must be added")
+	}
+	if _, ok := dispatcher.AddKindListenerContext(ctx, testET(999), 1, callback); ok {
+		t.Fatal("repeated listener must not be added")
+	}
+
+	// 存活的监听者保留，阈值随之增长
+	for i := 0; i < 100; i++ {
+		dispatcher.AddValueListenerContext(ctx, testEventID{1, testEV(i)}, testLID(i+2), callback)
+	}
+	if dispatcher.contextListeners.Len() > 101+minContextSweepLen || len(dispatcher.index.listeners) < 101 {
+		t.Fatal("live context listeners must be kept")
+	}
+}
+
+func TestFilteredListener(t *testing.T) {
+	dispatcher := NewDispatcher(WithRecoverPanic[testET, testEV, testLID]())
+	eventType := testET(1)
+	testErr := errors.New("test error")
+	var values []testEV
+
+	sub, ok := dispatcher.AddFilteredListener(eventType, 1, func(e testEvent) bool {
+		return e.EventID().Value > 100
+	}, func(e testEvent) error {
+		values = append(values, e.EventID().Value)
+		if e.EventID().Value == 200 {
+			return testErr
+		}
+		return nil
+	}, WithTimes(2))
+	if !ok || sub.Registration().Scope != ListenerScopeKind {
+		t.Fatal("filtered listener must be added as kind listener")
+	}
+	if _, ok := dispatcher.AddKindListener(eventType, 1, func(e testEvent) error { return nil }); ok {
+		t.Fatal("repeated listener must not be added")
+	}
+
+	for _, v := range []testEV{50, 150, 100, 200, 300} {
+		dispatcher.Dispatch(testEventID{eventType, v}, nil)
+	}
+	// 被过滤掉的事件不计入接收次数
+	if len(values) != 2 || values[0] != 150 || values[1] != 200 {
+		t.Fatal("values must be [150 200], got", values)
+	}
+	if stats := sub.Stats(); stats.Delivered != 2 || stats.FilteredOut != 2 || stats.Errors != 1 {
+		t.Fatal("stats mismatch", stats)
+	}
+	if sub.Active() {
+		t.Fatal("subscription must be inactive")
+	}
+
+	// 过滤器中的 panic 同样会被恢复
+	sub, _ = dispatcher.AddFilteredListener(eventType, 2, func(e testEvent) bool {
+		panic("filter panic")
+	}, func(e testEvent) error {
+		t.Fatal("callback must not be called")
+		return nil
+	})
+	var panicErr *ListenerPanicError[testET, testEV, testLID]
+	if err := dispatcher.Dispatch(testEventID{eventType, 1}, nil); !errors.As(err, &panicErr) {
+		t.Fatal("error must be ListenerPanicError")
+	}
+	if stats := sub.Stats(); stats.Delivered != 0 || stats.Errors != 1 {
+		t.Fatal("stats mismatch", stats)
+	}
+}
+
+func TestGlobalAndMultiKindListener(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	var order []testLID
+
+	record := func(lID testLID) testListenerCallback {
+		return func(e testEvent) error {
+			order = append(order, lID)
+			return nil
+		}
+	}
+	dispatcher.AddValueListener(testEventID{1, 1}, 1, record(1))
+	dispatcher.AddKindListener(1, 2, record(2))
+	dispatcher.AddMultiKindListener([]testET{1, 2}, 3, record(3))
+	dispatcher.AddGlobalListener(4, record(4))
+	if _, ok := dispatcher.AddGlobalListener(4, record(4)); ok {
+		t.Fatal("repeated global listener must not be added")
+	}
+	if _, ok := dispatcher.AddMultiKindListener([]testET{3}, 3, record(3)); ok {
+		t.Fatal("repeated multi-kind listener must not be added")
+	}
+
+	// 全局 -> 多类型 -> 类型 -> 值类型
+	dispatcher.Dispatch(testEventID{1, 1}, nil)
+	if len(order) != 4 || order[0] != 4 || order[1] != 3 || order[2] != 2 || order[3] != 1 {
+		t.Fatal("order must be [4 3 2 1], got", order)
+	}
+	order = nil
+	dispatcher.Dispatch(testEventID{2, 1}, nil)
+	dispatcher.Dispatch(testEventID{3, 1}, nil)
+	if len(order) != 3 || order[0] != 4 || order[1] != 3 || order[2] != 4 {
+		t.Fatal("order must be [4 3 4], got", order)
+	}
+
+	// 全局监听者停止传播后，不再派发给后续阶段
+	order = nil
+	dispatcher.AddGlobalListener(5, func(e testEvent) error {
+		return ErrStopPropagation
+	})
+	if cancelled, _ := dispatcher.DispatchCancelable(testEventID{1, 1}, nil); !cancelled {
+		t.Fatal("event must be cancelled")
+	}
+	if len(order) != 1 || order[0] != 4 {
+		t.Fatal("order must be [4], got", order)
+	}
+	if !dispatcher.RemGlobalListener(5) || !dispatcher.RemMultiKindListener(3) {
+		t.Fatal("listeners must be removed")
+	}
+
+	// 多类型监听者作为一个整体移除
+	order = nil
+	dispatcher.Dispatch(testEventID{2, 1}, nil)
+	if len(order) != 1 || order[0] != 4 {
+		t.Fatal("order must be [4], got", order)
+	}
+	if dispatcher.multiKindListeners != nil {
+		t.Fatal("multi-kind listeners must be empty")
+	}
+
+	// 派发过程中添加的全局监听者同样会被挂起
+	dispatcher.AddGlobalListener(6, func(e testEvent) error {
+		dispatcher.AddMultiKindListener([]testET{1}, 7, record(7))
+		dispatcher.RemGlobalListener(6)
+		return nil
+	}, WithPriority(1))
+	order = nil
+	dispatcher.Dispatch(testEventID{1, 2}, nil)
+	dispatcher.Dispatch(testEventID{1, 2}, nil)
+	if len(order) != 5 || order[0] != 4 || order[1] != 2 || order[2] != 4 || order[3] != 7 || order[4] != 2 {
+		t.Fatal("order must be [4 2 4 7 2], got", order)
+	}
+	if regs := dispatcher.ListenersOf(7); len(regs) != 1 || regs[0].Scope != ListenerScopeMultiKind {
+		t.Fatal("listener 7 must be multi-kind listener")
+	}
+	if !dispatcher.RemListener(4) || dispatcher.globalListeners != nil {
+		t.Fatal("global listeners must be empty")
+	}
+}
+
+func TestKindBubbling(t *testing.T) {
+	// 1 > 2 > 3，4 与 5 互为父类型
+	parents := map[testET]testET{2: 1, 3: 2, 4: 5, 5: 4}
+	dispatcher := NewDispatcher(WithKindParent[testET, testEV, testLID](func(kind testET) (testET, bool) {
+		parent, ok := parents[kind]
+		return parent, ok
+	}))
+	var order []testET
+
+	record := func(e testEvent) error {
+		order = append(order, e.CurrentKind())
+		return nil
+	}
+	dispatcher.AddKindListener(1, 1, record)
+	dispatcher.AddKindListener(3, 1, record)
+	dispatcher.AddValueListener(testEventID{3, 1}, 2, record)
+	// 值类型监听者不会接收冒泡的事件
+	dispatcher.AddValueListener(testEventID{1, 1}, 2, func(e testEvent) error {
+		t.Fatal("value listener of ancestor must not be called")
+		return nil
+	})
+
+	dispatcher.Dispatch(testEventID{3, 1}, nil)
+	if len(order) != 3 || order[0] != 3 || order[1] != 3 || order[2] != 1 {
+		t.Fatal("order must be [3 3 1], got", order)
+	}
+
+	// 事件类型本身没有监听者时，同样冒泡
+	order = nil
+	dispatcher.Dispatch(testEventID{2, 2}, nil)
+	if len(order) != 1 || order[0] != 1 {
+		t.Fatal("order must be [1], got", order)
+	}
+
+	// 任一层级停止传播后不再冒泡
+	order = nil
+	dispatcher.AddKindListener(2, 2, func(e testEvent) error {
+		if e.CurrentKind() != 2 || e.EventID().Kind != 3 {
+			t.Fatal("current kind must be 2")
+		}
+		return ErrStopPropagation
+	})
+	if cancelled, _ := dispatcher.DispatchCancelable(testEventID{3, 2}, nil); !cancelled {
+		t.Fatal("event must be cancelled")
+	}
+	if len(order) != 1 || order[0] != 3 {
+		t.Fatal("order must be [3], got", order)
+	}
+
+	// 祖先监听者的错误以 DispatchStageAncestor 标识
+	testErr := errors.New("test error")
+	dispatcher.RemKindListener(2, 2)
+	dispatcher.AddKindListener(1, 3, func(e testEvent) error {
+		return testErr
+	})
+	err := dispatcher.Dispatch(testEventID{3, 2}, nil)
+	var dispatchErrs *DispatchErrors[testET, testEV, testLID]
+	if !errors.As(err, &dispatchErrs) || dispatchErrs.Len() != 1 || dispatchErrs.Errors()[0].Stage != DispatchStageAncestor {
+		t.Fatal("error must be from ancestor stage")
+	}
+
+	// 父类型解析出现环时不会无限冒泡
+	order = nil
+	dispatcher.AddKindListener(4, 1, record)
+	dispatcher.AddKindListener(5, 1, record)
+	dispatcher.Dispatch(testEventID{4, 1}, nil)
+	if len(order) != 2 || order[0] != 4 || order[1] != 5 {
+		t.Fatal("order must be [4 5], got", order)
+	}
+}
+
+func TestKindBubblingMultiKind(t *testing.T) {
+	// 1 > 2 > 3
+	parents := map[testET]testET{2: 1, 3: 2}
+	dispatcher := NewDispatcher(WithKindParent[testET, testEV, testLID](func(kind testET) (testET, bool) {
+		parent, ok := parents[kind]
+		return parent, ok
+	}))
+	received := map[testLID][]testET{}
+	record := func(lID testLID) ListenerCallback[testET, testEV] {
+		return func(e testEvent) error {
+			received[lID] = append(received[lID], e.CurrentKind())
+			return nil
+		}
+	}
+
+	// 通过祖先类型接收冒泡的事件
+	dispatcher.AddMultiKindListener([]testET{1, 4}, 1, record(1))
+	// 同时监听事件类型本身及其祖先类型时，只接收一次
+	dispatcher.AddMultiKindListener([]testET{1, 2}, 2, record(2))
+	dispatcher.AddMultiKindListener([]testET{1, 3}, 3, record(3))
+
+	dispatcher.Dispatch(testEventID{2, 1}, nil)
+	if kinds := received[1]; len(kinds) != 1 || kinds[0] != 1 {
+		t.Fatal("kinds must be [1], got", kinds)
+	}
+	if kinds := received[2]; len(kinds) != 1 || kinds[0] != 2 {
+		t.Fatal("kinds must be [2], got", kinds)
+	}
+	if kinds := received[3]; len(kinds) != 1 || kinds[0] != 1 {
+		t.Fatal("kinds must be [1], got", kinds)
+	}
+
+	received = map[testLID][]testET{}
+	dispatcher.Dispatch(testEventID{3, 1}, nil)
+	if kinds := received[2]; len(kinds) != 1 || kinds[0] != 2 {
+		t.Fatal("kinds must be [2], got", kinds)
+	}
+	if kinds := received[3]; len(kinds) != 1 || kinds[0] != 3 {
+		t.Fatal("kinds must be [3], got", kinds)
+	}
+
+	// 冒泡至祖先类型的多类型监听者时停止传播，不再派发给祖先类型的类型监听者
+	received = map[testLID][]testET{}
+	dispatcher.AddKindListener(1, 4, record(4))
+	dispatcher.AddMultiKindListener([]testET{1}, 5, func(e testEvent) error {
+		if e.CurrentKind() != 1 {
+			t.Fatal("current kind must be", 1)
+		}
+		return ErrStopPropagation
+	})
+	if cancelled, _ := dispatcher.DispatchCancelable(testEventID{2, 1}, nil); !cancelled {
+		t.Fatal("event must be cancelled")
+	}
+	if len(received[4]) != 0 {
+		t.Fatal("kind listener of ancestor must not be called")
+	}
+}
+
 func BenchmarkAddHandler(b *testing.B) {
 	maxEventType := 100
 	maxEventVal := 1000
@@ -189,7 +1062,7 @@ func BenchmarkAddHandler(b *testing.B) {
 		dispatcher := NewDispatcher[testET, testEV, testLID]()
 
 		for i := 0; i < maxEventType; i++ {
-			dispatcher.AddKindListener(testET(i), 0, callback, false)
+			dispatcher.AddKindListener(testET(i), 0, callback)
 			for j := 0; j < maxEventVal; j++ {
 				dispatcher.AddValueListener(testEventID{testET(i), testEV(j)}, 0, callback)
 			}
@@ -220,3 +1093,44 @@ func BenchmarkAddHandler(b *testing.B) {
 	})
 
 }
+
+func BenchmarkDispatch(b *testing.B) {
+	maxEventType := 100
+	maxEventVal := 1000
+	callback := func(event testEvent) error {
+		return nil
+	}
+
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	cowDispatcher := NewConcurrentDispatcher[testET, testEV, testLID]()
+	for i := 0; i < maxEventType; i++ {
+		dispatcher.AddKindListener(testET(i), 0, callback)
+		cowDispatcher.AddKindListener(testET(i), 0, callback)
+		for j := 0; j < maxEventVal; j++ {
+			dispatcher.AddValueListener(testEventID{testET(i), testEV(j)}, 0, callback)
+			cowDispatcher.AddValueListener(testEventID{testET(i), testEV(j)}, 0, callback)
+		}
+	}
+
+	b.Run("list", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			dispatcher.Dispatch(testEventID{testET(i % maxEventType), testEV(i % maxEventVal)}, nil, nil)
+		}
+	})
+
+	b.Run("copy-on-write", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			cowDispatcher.Dispatch(testEventID{testET(i % maxEventType), testEV(i % maxEventVal)}, nil, nil)
+		}
+	})
+
+	b.Run("copy-on-write parallel", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			i := rand.Int()
+			for pb.Next() {
+				cowDispatcher.Dispatch(testEventID{testET(i % maxEventType), testEV(i % maxEventVal)}, nil, nil)
+				i++
+			}
+		})
+	})
+}
@@ -2,50 +2,270 @@ package gevent
 
 import (
 	"container/list"
+	"context"
 	"errors"
+	"time"
 )
 
 // ErrRemAfterDispatch 派发后移除
 // 监听者专用，返回该 error 即告诉派发器，在本次派发完成后移除它
 var ErrRemAfterDispatch = errors.New("remove after dispatch")
 
+// ErrStopPropagation 停止传播
+// 监听者专用，返回该 error 即告诉派发器，不再将本次事件派发给后续的监听者，本次事件被视为已取消
+var ErrStopPropagation = errors.New("stop propagation")
+
+// ErrStopBubbling 停止冒泡
+// 监听者专用，返回该 error 即告诉派发器，当前派发器的当前阶段派发完成后，不再将本次事件传递给派发链中的其它派发器
+// 与 ErrStopPropagation 不同，当前派发器的后续监听者仍会接收事件，本次事件也不会被视为已取消
+var ErrStopBubbling = errors.New("stop bubbling")
+
+// isListenerError 返回 err 是否为监听者产生的错误，即不为空且不是派发器专用的 error
+func isListenerError(err error) bool {
+	return err != nil && err != ErrStopPropagation && err != ErrRemAfterDispatch && err != ErrStopBubbling && err != errEventDropped
+}
+
+// dispatchState 派发状态，决定是否继续向后续的监听者派发事件
+type dispatchState int
+
+const (
+	dispatchContinue dispatchState = iota // 继续派发
+	dispatchStopped                       // 监听者停止了传播
+	dispatchAborted                       // 按照错误处理策略中止派发
+)
+
 // ListenerCallback 监听者回调
 type ListenerCallback[EventKind, EventValue comparable] func(Event[EventKind, EventValue]) error
 
+// EventFilter 事件过滤器
+// 返回 false 的事件不会被派发给监听者
+type EventFilter[EventKind, EventValue comparable] func(Event[EventKind, EventValue]) bool
+
 // listener 监听者
 // 记录监听者信息
 type listener[EventKind, EventValue, ListenerID comparable] struct {
-	id         ListenerID                              // 监听者ID
-	callback   ListenerCallback[EventKind, EventValue] // 监听者回调
-	once       bool                                    // 是否只监听一次
-	pendingRem bool                                    // 挂起等待移除
+	id         ListenerID                                        // 监听者ID
+	reg        Registration[EventKind, EventValue, ListenerID]   // 注册信息
+	callback   ListenerCallback[EventKind, EventValue]           // 监听者回调
+	times      int                                               // 剩余可接收事件的次数，小于 0 表示不限
+	deadline   time.Time                                         // 过期时间，零值表示不会过期
+	clock      Clock                                             // 时钟，用于判断是否过期
+	ctx        context.Context                                   // 所属的上下文，结束后监听者失效
+	filter     EventFilter[EventKind, EventValue]                // 过滤器，为空表示接收所有事件
+	kinds      map[EventKind]struct{}                            // 监听的事件类型，仅多类型监听者有效
+	pacing     *pacingBuffer[EventKind, EventValue]              // 事件缓冲，为空表示不节流
+	onReset    func()                                            // 重置时调用，channel 订阅用于关闭 channel
+	stats      ListenerStats                                     // 统计数据
+	replayErr  error                                             // 回放粘性事件时产生的错误
+	priority   int                                               // 优先级
+	pendingRem bool                                              // 挂起等待移除
+	index      *listenerIndex[EventKind, EventValue, ListenerID] // 所在的反向索引
+	indexElem  *list.Element                                     // 在反向索引中的 Elem
+	ctxList    *list.List                                        // 所在的上下文监听者列表
+	ctxElem    *list.Element                                     // 在上下文监听者列表中的 Elem
 }
 
-func newListener[EventKind, EventValue, ListenerID comparable](id ListenerID, callback ListenerCallback[EventKind, EventValue], once bool) *listener[EventKind, EventValue, ListenerID] {
+func newListener[EventKind, EventValue, ListenerID comparable](reg Registration[EventKind, EventValue, ListenerID], callback ListenerCallback[EventKind, EventValue], filter EventFilter[EventKind, EventValue], opts listenerOptions, clock Clock) *listener[EventKind, EventValue, ListenerID] {
 	if callback == nil {
 		panic("callback nil")
 	}
-	return &listener[EventKind, EventValue, ListenerID]{
-		id:         id,
+	l := &listener[EventKind, EventValue, ListenerID]{
+		id:         reg.ListenerID,
+		reg:        reg,
 		callback:   callback,
-		once:       once,
+		times:      -1,
+		deadline:   opts.expireAt(clock),
+		clock:      clock,
+		ctx:        opts.ctx,
+		filter:     filter,
+		priority:   opts.priority,
 		pendingRem: false,
 	}
+	if opts.times > 0 {
+		l.times = opts.times
+	}
+	if opts.pacing != pacingNone {
+		l.pacing = newPacingBuffer[EventKind, EventValue](opts.pacing, opts.interval)
+	}
+	return l
 }
 
-// dispatch 向监听者派发事件
-// 返回监听者产生的错误
-func (l *listener[EventKind, EventValue, ListenerID]) dispatch(evt Event[EventKind, EventValue]) error {
+// dispatch 向监听者派发事件，派发前先经过过滤器判断，节流的监听者可能将事件缓冲，等待 Tick 时投递
+// 返回事件是否被接收，以及监听者产生的错误
+// 若开启了 recoverPanic，过滤器及回调中的 panic 会被恢复为 *ListenerPanicError
+func (l *listener[EventKind, EventValue, ListenerID]) dispatch(evt Event[EventKind, EventValue], opts *dispatcherOptions[EventKind, EventValue, ListenerID]) (accepted bool, err error) {
 	if l.pendingRem {
 		// 已处于挂起移除状态，不再接收事件
-		return nil
+		return false, nil
+	}
+	if opts.recoverPanic {
+		defer func() {
+			if r := recover(); r != nil {
+				err = newListenerPanicError(l.id, evt.eventID, r)
+				l.stats.Errors++
+			}
+		}()
+	}
+	if l.filter != nil && !l.filter(evt) {
+		l.stats.FilteredOut++
+		return false, nil
+	}
+	if l.pacing != nil && !l.pacing.admit(evt, l.clock.Now()) {
+		// 事件已被缓冲
+		return false, nil
+	}
+	if err = l.deliver(evt, opts); err == errEventDropped {
+		return false, nil
+	}
+	return true, err
+}
+
+// deliver 将事件投递给监听者回调，返回监听者产生的错误
+// 若开启了 recoverPanic，回调中的 panic 会被恢复为 *ListenerPanicError
+func (l *listener[EventKind, EventValue, ListenerID]) deliver(evt Event[EventKind, EventValue], opts *dispatcherOptions[EventKind, EventValue, ListenerID]) (err error) {
+	if opts.recoverPanic {
+		defer func() {
+			if r := recover(); r != nil {
+				err = newListenerPanicError(l.id, evt.eventID, r)
+				l.stats.Errors++
+			}
+		}()
+	}
+	l.stats.Delivered++
+	err = l.callback(evt)
+	if err == errEventDropped {
+		// 事件被丢弃，不视为已接收
+		l.stats.Delivered--
+	} else if isListenerError(err) {
+		l.stats.Errors++
+	}
+	return err
+}
+
+// expired 返回监听者是否已过期，所属的上下文结束也视为过期
+func (l *listener[EventKind, EventValue, ListenerID]) expired() bool {
+	if l.ctx != nil && l.ctx.Err() != nil {
+		return true
+	}
+	return !l.deadline.IsZero() && !l.clock.Now().Before(l.deadline)
+}
+
+// consume 消耗一次接收事件的次数，返回次数是否已耗尽
+func (l *listener[EventKind, EventValue, ListenerID]) consume() bool {
+	if l.times < 0 {
+		return false
+	}
+	l.times--
+	return l.times <= 0
+}
+
+// matchKind 返回监听者是否接收当前派发的事件类型
+// 多类型监听者在冒泡过程中只通过最先到达的、其所监听的类型接收一次事件
+func (l *listener[EventKind, EventValue, ListenerID]) matchKind(evt *Event[EventKind, EventValue]) bool {
+	if l.kinds == nil {
+		return true
+	}
+	if _, ok := l.kinds[evt.current]; !ok {
+		return false
 	}
-	return l.callback(evt)
+	for _, kind := range evt.bubbled {
+		if _, ok := l.kinds[kind]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// getStats 返回监听者的统计数据
+func (l *listener[EventKind, EventValue, ListenerID]) getStats() ListenerStats {
+	return l.stats
+}
+
+// getReplayError 返回回放粘性事件时产生的错误
+func (l *listener[EventKind, EventValue, ListenerID]) getReplayError() error {
+	return l.replayErr
+}
+
+// isRegistered 返回监听者是否仍注册在派发器中，即未被移除且未挂起等待移除
+func (l *listener[EventKind, EventValue, ListenerID]) isRegistered() bool {
+	return l.callback != nil && !l.pendingRem
 }
 
-// reset 重置数据，解除引用
+// isActive 返回监听者是否仍然有效，即未被移除、未挂起等待移除且未过期
+func (l *listener[EventKind, EventValue, ListenerID]) isActive() bool {
+	return l.callback != nil && !l.pendingRem && !l.expired()
+}
+
+// reset 重置数据，解除引用，并从反向索引中移除
 func (l *listener[EventKind, EventValue, ListenerID]) reset() {
 	l.callback = nil
+	if l.index != nil {
+		l.index.remove(l)
+	}
+	if l.ctxList != nil {
+		l.ctxList.Remove(l.ctxElem)
+		l.ctxList = nil
+		l.ctxElem = nil
+	}
+	if l.onReset != nil {
+		onReset := l.onReset
+		l.onReset = nil
+		onReset()
+	}
+}
+
+// listenerIndex 监听者反向索引
+// 按照监听者ID记录监听者，用于查找或移除同一ID的所有注册
+type listenerIndex[EventKind, EventValue, ListenerID comparable] struct {
+	listeners map[ListenerID]*list.List // 监听者ID对应的监听者列表，按照添加顺序排列
+}
+
+func newListenerIndex[EventKind, EventValue, ListenerID comparable]() *listenerIndex[EventKind, EventValue, ListenerID] {
+	return &listenerIndex[EventKind, EventValue, ListenerID]{
+		listeners: map[ListenerID]*list.List{},
+	}
+}
+
+// add 将监听者加入索引
+func (idx *listenerIndex[EventKind, EventValue, ListenerID]) add(l *listener[EventKind, EventValue, ListenerID]) {
+	ls := idx.listeners[l.id]
+	if ls == nil {
+		ls = list.New()
+		idx.listeners[l.id] = ls
+	}
+	l.index = idx
+	l.indexElem = ls.PushBack(l)
+}
+
+// remove 将监听者移出索引
+func (idx *listenerIndex[EventKind, EventValue, ListenerID]) remove(l *listener[EventKind, EventValue, ListenerID]) {
+	if l.index != idx {
+		return
+	}
+	if ls := idx.listeners[l.id]; ls != nil {
+		ls.Remove(l.indexElem)
+		if ls.Len() == 0 {
+			delete(idx.listeners, l.id)
+		}
+	}
+	l.index = nil
+	l.indexElem = nil
+}
+
+// registrations 返回监听者ID对应的有效注册信息，按照添加顺序排列
+// expired 为 true 时包括已过期但尚未被移除的监听者
+func (idx *listenerIndex[EventKind, EventValue, ListenerID]) registrations(lID ListenerID, expired bool) []Registration[EventKind, EventValue, ListenerID] {
+	ls := idx.listeners[lID]
+	if ls == nil {
+		return nil
+	}
+	regs := make([]Registration[EventKind, EventValue, ListenerID], 0, ls.Len())
+	for elem := ls.Front(); elem != nil; elem = elem.Next() {
+		if l := elem.Value.(*listener[EventKind, EventValue, ListenerID]); l.isActive() || (expired && !l.pendingRem) {
+			regs = append(regs, l.reg)
+		}
+	}
+	return regs
 }
 
 // listenerContainer 监听者容器
@@ -54,6 +274,7 @@ type listenerContainer[EventKind, EventValue, ListenerID comparable] struct {
 	listenerList   *list.List                   // 监听者列表
 	listenerMap    map[ListenerID]*list.Element // 监听者 Elem Map
 	pendingRemList *list.List                   // 挂起移除列表，等待在事件派发完成后被移除的监听者
+	pendingMoves   []*list.Element              // 挂起调整顺序的监听者，等待在事件派发完成后按优先级调整位置
 	dispatching    int                          // 派发状态计数
 }
 
@@ -66,20 +287,67 @@ func newListenerContainer[EventKind, EventValue, ListenerID comparable]() *liste
 
 // addListener 添加监听者
 // 不能重复添加相同ID的监听者
+// 监听者按照优先级从高到低排列，相同优先级的监听者按照添加顺序排列
 func (ls *listenerContainer[EventKind, EventValue, ListenerID]) addListener(l *listener[EventKind, EventValue, ListenerID]) bool {
-	if ls.dispatching > 0 {
-		// 不能在派发事件状态下，嵌套添加监听者
-		panic("add listener on dispatching")
-	}
 	if elem, ok := ls.listenerMap[l.id]; ok {
 		return false
 	} else {
-		elem = ls.listenerList.PushBack(l)
+		if mark := ls.lastNotLower(l.priority, nil); mark != nil {
+			elem = ls.listenerList.InsertAfter(l, mark)
+		} else {
+			elem = ls.listenerList.PushFront(l)
+		}
 		ls.listenerMap[l.id] = elem
 		return true
 	}
 }
 
+// lastNotLower 返回最后一个优先级不低于 priority 的监听者元素，忽略 exclude
+func (ls *listenerContainer[EventKind, EventValue, ListenerID]) lastNotLower(priority int, exclude *list.Element) *list.Element {
+	for elem := ls.listenerList.Back(); elem != nil; elem = elem.Prev() {
+		if elem != exclude && elem.Value.(*listener[EventKind, EventValue, ListenerID]).priority >= priority {
+			return elem
+		}
+	}
+	return nil
+}
+
+// setPriority 修改监听者的优先级
+// 派发过程中不能调整列表顺序，需挂起等待派发完成后调整
+func (ls *listenerContainer[EventKind, EventValue, ListenerID]) setPriority(lID ListenerID, priority int) bool {
+	elem, ok := ls.listenerMap[lID]
+	if !ok {
+		return false
+	}
+	l := elem.Value.(*listener[EventKind, EventValue, ListenerID])
+	if l.pendingRem {
+		return false
+	}
+	l.priority = priority
+	if ls.dispatching > 0 {
+		ls.pendingMoves = append(ls.pendingMoves, elem)
+	} else {
+		ls.moveByPriority(elem)
+	}
+	return true
+}
+
+// moveByPriority 按照优先级调整监听者的位置，排在相同优先级监听者的末尾
+func (ls *listenerContainer[EventKind, EventValue, ListenerID]) moveByPriority(elem *list.Element) {
+	l := elem.Value.(*listener[EventKind, EventValue, ListenerID])
+	if mark := ls.lastNotLower(l.priority, elem); mark != nil {
+		ls.listenerList.MoveAfter(elem, mark)
+	} else {
+		ls.listenerList.MoveToFront(elem)
+	}
+}
+
+// hasListener 返回是否存在未挂起移除的监听者
+func (ls *listenerContainer[EventKind, EventValue, ListenerID]) hasListener(lID ListenerID) bool {
+	elem, ok := ls.listenerMap[lID]
+	return ok && !elem.Value.(*listener[EventKind, EventValue, ListenerID]).pendingRem
+}
+
 // remListener 移除监听者
 func (ls *listenerContainer[EventKind, EventValue, ListenerID]) remListener(lID ListenerID) bool {
 	elem, ok := ls.listenerMap[lID]
@@ -123,26 +391,46 @@ func (ls *listenerContainer[EventKind, EventValue, ListenerID]) noListener() boo
 }
 
 // dispatch 向监听者们派发事件
-// 返回监听者们产生的错误
-func (ls *listenerContainer[EventKind, EventValue, ListenerID]) dispatch(event Event[EventKind, EventValue]) error {
+// 监听者们产生的错误按 stage 封装后，依照错误处理策略追加到 errs
+// 返回派发状态，以及追加后的 errs
+func (ls *listenerContainer[EventKind, EventValue, ListenerID]) dispatch(event Event[EventKind, EventValue], stage DispatchStage, opts *dispatcherOptions[EventKind, EventValue, ListenerID], errs []*DispatchError[EventKind, EventValue, ListenerID]) (dispatchState, []*DispatchError[EventKind, EventValue, ListenerID]) {
 	ls.dispatching++
+	// 即使回调 panic，也需要保证派发状态计数及挂起的操作被正确处理
+	defer ls.endDispatch()
 
-	var errs []error
+	state := dispatchContinue
 	elem := ls.listenerList.Front()
-	for elem != nil {
+	for elem != nil && state == dispatchContinue {
 		l := elem.Value.(*listener[EventKind, EventValue, ListenerID])
-		if !l.pendingRem {
-			err := l.dispatch(event)
-			if err != nil && err != ErrRemAfterDispatch {
-				errs = append(errs, err)
+		if !l.pendingRem && l.expired() {
+			// 已过期，不再接收事件，通过挂起移除的方式移除
+			ls.pendingRemListener(l, elem)
+		} else if !l.pendingRem && l.matchKind(&event) {
+			accepted, err := l.dispatch(event, opts)
+			if err == ErrStopPropagation {
+				state = dispatchStopped
+			} else if err == ErrStopBubbling {
+				event.stopBubbling()
+			} else if isListenerError(err) {
+				errs, state = opts.onError(errs, &DispatchError[EventKind, EventValue, ListenerID]{
+					EventID:    event.eventID,
+					Stage:      stage,
+					ListenerID: l.id,
+					Err:        err,
+				})
 			}
-			if l.once || err == ErrRemAfterDispatch {
+			if (accepted && l.consume()) || err == ErrRemAfterDispatch {
 				ls.pendingRemListener(l, elem)
 			}
 		}
 		elem = elem.Next()
 	}
 
+	return state, errs
+}
+
+// endDispatch 结束派发，最外层派发结束时，处理挂起的移除及顺序调整
+func (ls *listenerContainer[EventKind, EventValue, ListenerID]) endDispatch() {
 	ls.dispatching--
 	if ls.dispatching < 0 {
 		ls.dispatching = 0
@@ -160,11 +448,14 @@ func (ls *listenerContainer[EventKind, EventValue, ListenerID]) dispatch(event E
 		ls.pendingRemList = nil
 	}
 
-	if len(errs) > 0 {
-		return &dispatchErrors{errors: errs}
+	if ls.dispatching == 0 && ls.pendingMoves != nil {
+		for _, elem := range ls.pendingMoves {
+			if ls.listenerMap[elem.Value.(*listener[EventKind, EventValue, ListenerID]).id] == elem {
+				ls.moveByPriority(elem)
+			}
+		}
+		ls.pendingMoves = nil
 	}
-
-	return nil
 }
 
 // clear 清理容器，移除所有监听者
@@ -174,6 +465,7 @@ func (ls *listenerContainer[EventKind, EventValue, ListenerID]) clear() {
 		return
 	}
 	ls.listenerMap = nil
+	ls.pendingMoves = nil
 	if ls.pendingRemList != nil {
 		ls.pendingRemList.Init()
 		elem := ls.pendingRemList.Front()
@@ -198,6 +490,27 @@ func (ls *listenerContainer[EventKind, EventValue, ListenerID]) clear() {
 	}
 }
 
+// dispatchContainer 向 *lc 中的监听者们派发事件，派发后容器中已没有监听者时将其置空
+func dispatchContainer[EventKind, EventValue, ListenerID comparable](lc **listenerContainer[EventKind, EventValue, ListenerID], event Event[EventKind, EventValue], stage DispatchStage, opts *dispatcherOptions[EventKind, EventValue, ListenerID], errs []*DispatchError[EventKind, EventValue, ListenerID]) (dispatchState, []*DispatchError[EventKind, EventValue, ListenerID]) {
+	state, errs := (*lc).dispatch(event, stage, opts, errs)
+	if (*lc).noListener() {
+		*lc = nil
+	}
+	return state, errs
+}
+
+// remContainerListener 从 *lc 中移除监听者，移除后容器中已没有监听者时将其置空
+func remContainerListener[EventKind, EventValue, ListenerID comparable](lc **listenerContainer[EventKind, EventValue, ListenerID], lID ListenerID) bool {
+	if *lc == nil {
+		return false
+	}
+	rem := (*lc).remListener(lID)
+	if (*lc).noListener() {
+		*lc = nil
+	}
+	return rem
+}
+
 // kindListenerContainer 按事件类型划分的监听者容器
 type kindListenerContainer[EventKind, EventValue, ListenerID comparable] struct {
 	kindListeners  *listenerContainer[EventKind, EventValue, ListenerID]                // 类型事件监听者
@@ -211,16 +524,17 @@ func newKindListenerContainer[EventKind, EventValue, ListenerID comparable]() *k
 
 // addKindListener 添加类型事件监听者
 func (kls *kindListenerContainer[EventKind, EventValue, ListenerID]) addKindListener(l *listener[EventKind, EventValue, ListenerID]) bool {
-	if kls.dispatching > 0 {
-		// 不能在派发事件状态下，嵌套添加监听者
-		panic("add kind listener on dispatching")
-	}
 	if kls.kindListeners == nil {
 		kls.kindListeners = newListenerContainer[EventKind, EventValue, ListenerID]()
 	}
 	return kls.kindListeners.addListener(l)
 }
 
+// hasKindListener 返回是否存在未挂起移除的类型事件监听者
+func (kls *kindListenerContainer[EventKind, EventValue, ListenerID]) hasKindListener(lID ListenerID) bool {
+	return kls.kindListeners != nil && kls.kindListeners.hasListener(lID)
+}
+
 // remKindListener 移除类型事件监听者
 func (kls *kindListenerContainer[EventKind, EventValue, ListenerID]) remKindListener(lID ListenerID) bool {
 	if kls.kindListeners == nil {
@@ -235,10 +549,6 @@ func (kls *kindListenerContainer[EventKind, EventValue, ListenerID]) remKindList
 
 // addValueListener 添加值类型事件监听者
 func (kls *kindListenerContainer[EventKind, EventValue, ListenerID]) addValueListener(value EventValue, l *listener[EventKind, EventValue, ListenerID]) bool {
-	if kls.dispatching > 0 {
-		// 不能在派发事件状态下，嵌套添加监听者
-		panic("add value listener on dispatching")
-	}
 	if kls.valueListeners == nil {
 		kls.valueListeners = map[EventValue]*listenerContainer[EventKind, EventValue, ListenerID]{}
 	}
@@ -250,6 +560,12 @@ func (kls *kindListenerContainer[EventKind, EventValue, ListenerID]) addValueLis
 	return lc.addListener(l)
 }
 
+// hasValueListener 返回是否存在未挂起移除的值类型事件监听者
+func (kls *kindListenerContainer[EventKind, EventValue, ListenerID]) hasValueListener(value EventValue, lID ListenerID) bool {
+	lc := kls.valueListeners[value]
+	return lc != nil && lc.hasListener(lID)
+}
+
 // remValueListener 移除值类型事件监听者
 func (kls *kindListenerContainer[EventKind, EventValue, ListenerID]) remValueListener(value EventValue, lID ListenerID) bool {
 	lc := kls.valueListeners[value]
@@ -272,34 +588,21 @@ func (kls *kindListenerContainer[EventKind, EventValue, ListenerID]) noListener(
 }
 
 // dispatch 向监听者们派发事件
-// 先派发类型事件，再配发值类事件
-func (kls *kindListenerContainer[EventKind, EventValue, ListenerID]) dispatch(evt Event[EventKind, EventValue]) error {
+// 先派发类型事件，再配发值类事件，类型事件的派发被停止或中止时，不再派发值类事件
+// 监听者们产生的错误追加到 errs，返回派发状态，以及追加后的 errs
+func (kls *kindListenerContainer[EventKind, EventValue, ListenerID]) dispatch(evt Event[EventKind, EventValue], opts *dispatcherOptions[EventKind, EventValue, ListenerID], errs []*DispatchError[EventKind, EventValue, ListenerID]) (dispatchState, []*DispatchError[EventKind, EventValue, ListenerID]) {
 	kls.dispatching++
-	var errs []error
+	defer kls.endDispatch()
+	state := dispatchContinue
 
 	if kls.kindListeners != nil {
-		if err := kls.kindListeners.dispatch(evt); err != nil {
-			errs = append(errs, &dispatchError[EventKind, EventValue]{
-				dt:      "kind",
-				eventID: evt.eventID,
-				err:     err,
-			})
-		}
-		if kls.kindListeners.noListener() {
-			kls.kindListeners = nil
-		}
+		state, errs = dispatchContainer(&kls.kindListeners, evt, DispatchStageKind, opts, errs)
 	}
 
-	if kls.valueListeners != nil {
+	if kls.valueListeners != nil && state == dispatchContinue {
 		lc := kls.valueListeners[evt.eventID.Value]
 		if lc != nil {
-			if err := lc.dispatch(evt); err != nil {
-				errs = append(errs, &dispatchError[EventKind, EventValue]{
-					dt:      "value",
-					eventID: evt.eventID,
-					err:     err,
-				})
-			}
+			state, errs = lc.dispatch(evt, DispatchStageValue, opts, errs)
 			if lc.noListener() {
 				delete(kls.valueListeners, evt.eventID.Value)
 				if len(kls.valueListeners) == 0 {
@@ -309,16 +612,25 @@ func (kls *kindListenerContainer[EventKind, EventValue, ListenerID]) dispatch(ev
 		}
 	}
 
+	return state, errs
+}
+
+// dispatchKind 仅向类型事件监听者派发事件，用于向祖先类型冒泡
+func (kls *kindListenerContainer[EventKind, EventValue, ListenerID]) dispatchKind(evt Event[EventKind, EventValue], stage DispatchStage, opts *dispatcherOptions[EventKind, EventValue, ListenerID], errs []*DispatchError[EventKind, EventValue, ListenerID]) (dispatchState, []*DispatchError[EventKind, EventValue, ListenerID]) {
+	if kls.kindListeners == nil {
+		return dispatchContinue, errs
+	}
+	kls.dispatching++
+	defer kls.endDispatch()
+	return dispatchContainer(&kls.kindListeners, evt, stage, opts, errs)
+}
+
+// endDispatch 结束派发
+func (kls *kindListenerContainer[EventKind, EventValue, ListenerID]) endDispatch() {
 	kls.dispatching--
 	if kls.dispatching < 0 {
 		kls.dispatching = 0
 	}
-
-	if len(errs) > 0 {
-		return &dispatchErrors{errors: errs}
-	}
-
-	return nil
 }
 
 // clear 清理容器，移除所有监听者
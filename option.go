@@ -0,0 +1,216 @@
+package gevent
+
+import (
+	"context"
+	"time"
+)
+
+// ListenerOption 监听者选项
+type ListenerOption func(*listenerOptions)
+
+// listenerOptions 添加监听者时指定的选项
+type listenerOptions struct {
+	times    int             // 最多接收事件的次数，0 表示不限
+	ttl      time.Duration   // 存活时长，0 表示不限
+	deadline time.Time       // 过期时间，零值表示不限
+	ctx      context.Context // 所属的上下文，结束后监听者失效，为空表示不限
+	priority int             // 优先级
+	pacing   pacingMode      // 节流方式
+	interval time.Duration   // 节流间隔，debounce 为静默时长，throttle 为最短投递间隔
+}
+
+func newListenerOptions(opts []ListenerOption) listenerOptions {
+	var o listenerOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// WithOnce 只监听一次，接收一次事件后即被移除，等同于 WithTimes(1)
+func WithOnce() ListenerOption {
+	return WithTimes(1)
+}
+
+// WithTimes 最多接收 n 次事件，接收 n 次事件后即被移除
+// n 必须大于 0
+func WithTimes(n int) ListenerOption {
+	if n <= 0 {
+		panic("listener times must be positive")
+	}
+	return func(o *listenerOptions) {
+		o.times = n
+	}
+}
+
+// WithTTL 监听者自添加起存活 d 时长，过期后不再接收事件，并在下次派发时被移除
+// 与 WithDeadline 同时指定时，以较早的过期时间为准
+func WithTTL(d time.Duration) ListenerOption {
+	return func(o *listenerOptions) {
+		o.ttl = d
+	}
+}
+
+// WithDeadline 监听者在 t 时刻过期，过期后不再接收事件，并在下次派发时被移除
+// 与 WithTTL 同时指定时，以较早的过期时间为准
+func WithDeadline(t time.Time) ListenerOption {
+	return func(o *listenerOptions) {
+		o.deadline = t
+	}
+}
+
+// WithPriority 指定监听者的优先级，默认为 0
+// 优先级高的监听者先接收事件，优先级相同的监听者按照添加顺序接收事件
+func WithPriority(priority int) ListenerOption {
+	return func(o *listenerOptions) {
+		o.priority = priority
+	}
+}
+
+// WithDebounce 防抖，同一事件ID的事件在最后一次产生后静默 d 时长，才投递最后一次的事件
+// 缓冲的事件由 Dispatcher.Tick 投递；与 WithThrottle、WithCoalesce 互斥，以最后指定的为准
+// d 必须大于 0，仅 Dispatcher 及基于其的派发器支持
+func WithDebounce(d time.Duration) ListenerOption {
+	if d <= 0 {
+		panic("listener debounce interval must be positive")
+	}
+	return func(o *listenerOptions) {
+		o.pacing = pacingDebounce
+		o.interval = d
+	}
+}
+
+// WithThrottle 节流，同一事件ID的事件每 d 时长最多投递一次
+// 间隔内的首个事件立即投递，其余事件只保留最后一次，在间隔结束后由 Dispatcher.Tick 投递；
+// 与 WithDebounce、WithCoalesce 互斥，以最后指定的为准
+// d 必须大于 0，仅 Dispatcher 及基于其的派发器支持
+func WithThrottle(d time.Duration) ListenerOption {
+	if d <= 0 {
+		panic("listener throttle interval must be positive")
+	}
+	return func(o *listenerOptions) {
+		o.pacing = pacingThrottle
+		o.interval = d
+	}
+}
+
+// WithCoalesce 合并，两次 Dispatcher.Tick 之间同一事件ID的事件合并为一次投递
+// 投递的事件携带最后一次事件的参数及产生者，所有事件的参数可以通过 Event.Payloads 获取；
+// 与 WithDebounce、WithThrottle 互斥，以最后指定的为准
+// 仅 Dispatcher 及基于其的派发器支持
+func WithCoalesce() ListenerOption {
+	return func(o *listenerOptions) {
+		o.pacing = pacingCoalesce
+		o.interval = 0
+	}
+}
+
+// expireAt 根据添加时的时钟计算监听者的过期时间，零值表示不会过期
+func (o *listenerOptions) expireAt(clock Clock) time.Time {
+	deadline := o.deadline
+	if o.ttl != 0 {
+		if ttlDeadline := clock.Now().Add(o.ttl); deadline.IsZero() || ttlDeadline.Before(deadline) {
+			deadline = ttlDeadline
+		}
+	}
+	return deadline
+}
+
+// newContextListenerOptions 创建与 ctx 绑定的监听者选项
+// ctx 已结束时返回 false
+func newContextListenerOptions(ctx context.Context, opts []ListenerOption) (listenerOptions, bool) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	o := newListenerOptions(opts)
+	o.ctx = ctx
+	return o, ctx.Err() == nil
+}
+
+// DispatcherOption 派发器选项
+type DispatcherOption[EventKind, EventValue, ListenerID comparable] func(*dispatcherOptions[EventKind, EventValue, ListenerID])
+
+// dispatcherOptions 创建派发器时指定的选项
+type dispatcherOptions[EventKind, EventValue, ListenerID comparable] struct {
+	recoverPanic bool                                            // 是否恢复监听者回调中的 panic
+	errorPolicy  ErrorPolicy                                     // 错误处理策略
+	errorHandler ErrorHandler[EventKind, EventValue, ListenerID] // 错误处理器
+	clock        Clock                                           // 时钟
+	kindParent   KindParentResolver[EventKind]                   // 事件类型的父类型解析器
+}
+
+func newDispatcherOptions[EventKind, EventValue, ListenerID comparable](opts []DispatcherOption[EventKind, EventValue, ListenerID]) dispatcherOptions[EventKind, EventValue, ListenerID] {
+	var o dispatcherOptions[EventKind, EventValue, ListenerID]
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	if o.clock == nil {
+		o.clock = systemClock{}
+	}
+	return o
+}
+
+// WithRecoverPanic 恢复监听者回调中的 panic
+// panic 会被转换为 *ListenerPanicError，作为该监听者产生的错误返回，不影响其它监听者接收事件
+func WithRecoverPanic[EventKind, EventValue, ListenerID comparable]() DispatcherOption[EventKind, EventValue, ListenerID] {
+	return func(o *dispatcherOptions[EventKind, EventValue, ListenerID]) {
+		o.recoverPanic = true
+	}
+}
+
+// WithErrorPolicy 指定派发器默认的错误处理策略
+func WithErrorPolicy[EventKind, EventValue, ListenerID comparable](policy ErrorPolicy) DispatcherOption[EventKind, EventValue, ListenerID] {
+	return func(o *dispatcherOptions[EventKind, EventValue, ListenerID]) {
+		o.errorPolicy = policy
+	}
+}
+
+// WithErrorHandler 指定错误处理器，并将派发器默认的错误处理策略设置为 ErrorPolicyHandle
+func WithErrorHandler[EventKind, EventValue, ListenerID comparable](handler ErrorHandler[EventKind, EventValue, ListenerID]) DispatcherOption[EventKind, EventValue, ListenerID] {
+	return func(o *dispatcherOptions[EventKind, EventValue, ListenerID]) {
+		o.errorPolicy = ErrorPolicyHandle
+		o.errorHandler = handler
+	}
+}
+
+// WithClock 指定派发器使用的时钟，默认使用系统时钟
+func WithClock[EventKind, EventValue, ListenerID comparable](clock Clock) DispatcherOption[EventKind, EventValue, ListenerID] {
+	return func(o *dispatcherOptions[EventKind, EventValue, ListenerID]) {
+		o.clock = clock
+	}
+}
+
+// KindParentResolver 事件类型的父类型解析器
+// 返回 kind 的父类型，没有父类型时返回 false
+type KindParentResolver[EventKind comparable] func(kind EventKind) (EventKind, bool)
+
+// WithKindParent 指定事件类型的父类型解析器，使事件类型构成层级结构
+// 派发事件时，在派发给事件类型本身的监听者之后，依次冒泡派发给监听各级祖先类型的多类型监听者及类型事件监听者，
+// 多类型监听者在整个冒泡过程中至多接收一次事件；任一层级停止传播或中止派发后，不再继续冒泡
+func WithKindParent[EventKind, EventValue, ListenerID comparable](resolver KindParentResolver[EventKind]) DispatcherOption[EventKind, EventValue, ListenerID] {
+	return func(o *dispatcherOptions[EventKind, EventValue, ListenerID]) {
+		o.kindParent = resolver
+	}
+}
+
+// onError 按照错误处理策略处理监听者产生的错误
+// 返回追加后的 errs，以及之后的派发状态
+func (o *dispatcherOptions[EventKind, EventValue, ListenerID]) onError(errs []*DispatchError[EventKind, EventValue, ListenerID], err *DispatchError[EventKind, EventValue, ListenerID]) ([]*DispatchError[EventKind, EventValue, ListenerID], dispatchState) {
+	switch o.errorPolicy {
+	case ErrorPolicyFailFast:
+		return append(errs, err), dispatchAborted
+	case ErrorPolicyHandle:
+		if o.errorHandler == nil {
+			// 未指定错误处理器时收集错误，避免错误被静默丢弃
+			return append(errs, err), dispatchContinue
+		}
+		o.errorHandler(err.EventID, err.ListenerID, err.Err)
+		return errs, dispatchContinue
+	default:
+		return append(errs, err), dispatchContinue
+	}
+}
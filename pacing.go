@@ -0,0 +1,134 @@
+package gevent
+
+import "time"
+
+// pacingMode 监听者的节流方式
+type pacingMode int
+
+const (
+	pacingNone     pacingMode = iota // 不节流，事件立即投递
+	pacingDebounce                   // 防抖，静默一段时间后投递最后一次的事件
+	pacingThrottle                   // 节流，每个间隔最多投递一次
+	pacingCoalesce                   // 合并，每次 Tick 将同一事件ID的事件合并投递
+)
+
+// pacedEvent 按事件ID缓冲的事件
+type pacedEvent[EventKind, EventValue comparable] struct {
+	evt     Event[EventKind, EventValue] // 等待投递的事件
+	last    time.Time                    // debounce 为最后一次产生事件的时间，throttle 为最后一次投递的时间
+	pending bool                         // 是否有等待投递的事件
+	seq     uint64                       // 开始缓冲时的序号，用于避免投递过程中再次缓冲的事件在同一次 Tick 中被投递
+}
+
+// pacingBuffer 监听者的事件缓冲
+// 按照事件ID缓冲事件，由派发器的 Tick 按照首次缓冲的顺序投递到期的事件
+type pacingBuffer[EventKind, EventValue comparable] struct {
+	mode     pacingMode                                                            // 节流方式
+	interval time.Duration                                                         // 节流间隔
+	events   map[EventID[EventKind, EventValue]]*pacedEvent[EventKind, EventValue] // 按事件ID缓冲的事件
+	order    []EventID[EventKind, EventValue]                                      // 事件ID的缓冲顺序
+	seq      uint64                                                                // 下一个缓冲序号
+}
+
+func newPacingBuffer[EventKind, EventValue comparable](mode pacingMode, interval time.Duration) *pacingBuffer[EventKind, EventValue] {
+	return &pacingBuffer[EventKind, EventValue]{
+		mode:     mode,
+		interval: interval,
+		events:   map[EventID[EventKind, EventValue]]*pacedEvent[EventKind, EventValue]{},
+	}
+}
+
+// admit 接收事件，返回事件是否应立即投递，否则事件被缓冲，等待 Tick 时投递
+func (b *pacingBuffer[EventKind, EventValue]) admit(evt Event[EventKind, EventValue], now time.Time) bool {
+	evt.stopped = nil
+	e := b.events[evt.eventID]
+	if b.mode == pacingThrottle {
+		if e == nil {
+			b.add(evt.eventID, &pacedEvent[EventKind, EventValue]{last: now})
+			return true
+		}
+		if !now.Before(e.last.Add(b.interval)) {
+			// 间隔已结束，立即投递，丢弃间隔内缓冲的事件
+			e.last = now
+			e.pending = false
+			e.evt = Event[EventKind, EventValue]{}
+			return true
+		}
+		if !e.pending {
+			e.pending = true
+			e.seq = b.nextSeq()
+		}
+		e.evt = evt
+		return false
+	}
+
+	if e == nil {
+		e = &pacedEvent[EventKind, EventValue]{pending: true, seq: b.nextSeq()}
+		b.add(evt.eventID, e)
+		if b.mode == pacingCoalesce {
+			evt.payloads = []interface{}{evt.param}
+		}
+	} else if b.mode == pacingCoalesce {
+		evt.payloads = append(e.evt.payloads, evt.param)
+	}
+	e.evt = evt
+	e.last = now
+	return false
+}
+
+// next 按照缓冲顺序取出一个到期的事件，只取 seq 之前开始缓冲的事件
+func (b *pacingBuffer[EventKind, EventValue]) next(now time.Time, seq uint64) (Event[EventKind, EventValue], bool) {
+	for i := 0; i < len(b.order); {
+		evtId := b.order[i]
+		e := b.events[evtId]
+		if !e.pending {
+			// 节流间隔结束且没有缓冲的事件，无须再记录
+			if !now.Before(e.last.Add(b.interval)) {
+				b.remove(i)
+				continue
+			}
+			i++
+			continue
+		}
+		if e.seq >= seq || (b.mode != pacingCoalesce && now.Before(e.last.Add(b.interval))) {
+			i++
+			continue
+		}
+		evt := e.evt
+		if b.mode == pacingThrottle {
+			e.last = now
+			e.pending = false
+			e.evt = Event[EventKind, EventValue]{}
+		} else {
+			b.remove(i)
+		}
+		return evt, true
+	}
+	return Event[EventKind, EventValue]{}, false
+}
+
+// add 记录事件ID的缓冲
+func (b *pacingBuffer[EventKind, EventValue]) add(evtId EventID[EventKind, EventValue], e *pacedEvent[EventKind, EventValue]) {
+	b.events[evtId] = e
+	b.order = append(b.order, evtId)
+}
+
+// remove 移除第 i 个事件ID的缓冲
+func (b *pacingBuffer[EventKind, EventValue]) remove(i int) {
+	delete(b.events, b.order[i])
+	copy(b.order[i:], b.order[i+1:])
+	b.order = b.order[:len(b.order)-1]
+}
+
+// nextSeq 返回下一个缓冲序号
+func (b *pacingBuffer[EventKind, EventValue]) nextSeq() uint64 {
+	seq := b.seq
+	b.seq++
+	return seq
+}
+
+// clear 丢弃所有缓冲的事件
+func (b *pacingBuffer[EventKind, EventValue]) clear() {
+	b.events = map[EventID[EventKind, EventValue]]*pacedEvent[EventKind, EventValue]{}
+	b.order = nil
+}
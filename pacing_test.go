@@ -0,0 +1,172 @@
+package gevent
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDebounceListener(t *testing.T) {
+	clock := newTestClock()
+	dispatcher := NewDispatcher[testET, testEV, testLID](WithClock[testET, testEV, testLID](clock))
+	var params []interface{}
+
+	dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		params = append(params, e.Param())
+		return nil
+	}, WithDebounce(time.Second))
+
+	for i := 1; i <= 3; i++ {
+		dispatcher.Dispatch(testEventID{1, 1}, nil, i)
+		clock.Advance(500 * time.Millisecond)
+	}
+	dispatcher.Dispatch(testEventID{1, 2}, nil, 10)
+	if dispatcher.Tick(); len(params) != 0 {
+		t.Fatal("events must be debounced")
+	}
+
+	// 事件 {1 1} 已静默 1s，事件 {1 2} 尚未静默足够的时长
+	clock.Advance(500 * time.Millisecond)
+	if dispatcher.Tick(); len(params) != 1 || params[0] != 3 {
+		t.Fatal("params must be [3], got", params)
+	}
+	clock.Advance(500 * time.Millisecond)
+	if dispatcher.Tick(); len(params) != 2 || params[1] != 10 {
+		t.Fatal("params must be [3 10], got", params)
+	}
+	if dispatcher.Tick(); len(params) != 2 {
+		t.Fatal("params must be [3 10], got", params)
+	}
+}
+
+func TestThrottleListener(t *testing.T) {
+	clock := newTestClock()
+	dispatcher := NewDispatcher[testET, testEV, testLID](WithClock[testET, testEV, testLID](clock))
+	var params []interface{}
+
+	dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		params = append(params, e.Param())
+		return nil
+	}, WithThrottle(time.Second))
+
+	// 间隔内的首个事件立即投递
+	for i := 1; i <= 3; i++ {
+		dispatcher.Dispatch(testEventID{1, 1}, nil, i)
+	}
+	if len(params) != 1 || params[0] != 1 {
+		t.Fatal("params must be [1], got", params)
+	}
+	if dispatcher.Tick(); len(params) != 1 {
+		t.Fatal("params must be [1], got", params)
+	}
+
+	// 间隔结束后投递间隔内的最后一次事件
+	clock.Advance(time.Second)
+	if dispatcher.Tick(); len(params) != 2 || params[1] != 3 {
+		t.Fatal("params must be [1 3], got", params)
+	}
+	dispatcher.Dispatch(testEventID{1, 1}, nil, 4)
+	if len(params) != 2 {
+		t.Fatal("params must be [1 3], got", params)
+	}
+
+	clock.Advance(time.Second)
+	dispatcher.Dispatch(testEventID{1, 1}, nil, 5)
+	if len(params) != 3 || params[2] != 5 {
+		t.Fatal("params must be [1 3 5], got", params)
+	}
+	clock.Advance(time.Second)
+	if dispatcher.Tick(); len(params) != 3 {
+		t.Fatal("params must be [1 3 5], got", params)
+	}
+}
+
+func TestCoalesceListener(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	var values []testEV
+	var payloads [][]interface{}
+
+	dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		values = append(values, e.EventID().Value)
+		payloads = append(payloads, e.Payloads())
+		if e.EventID().Value == 1 {
+			// 投递过程中再次缓冲的事件留待下次 Tick
+			dispatcher.Dispatch(testEventID{1, 1}, nil, 100)
+		}
+		return nil
+	}, WithCoalesce())
+
+	dispatcher.Dispatch(testEventID{1, 1}, nil, 1)
+	dispatcher.Dispatch(testEventID{1, 2}, nil, 10)
+	dispatcher.Dispatch(testEventID{1, 1}, nil, 2)
+	dispatcher.Dispatch(testEventID{1, 1}, nil, 3)
+	if len(values) != 0 {
+		t.Fatal("events must be coalesced")
+	}
+
+	dispatcher.Tick()
+	if len(values) != 2 || values[0] != 1 || values[1] != 2 {
+		t.Fatal("values must be [1 2], got", values)
+	}
+	if len(payloads[0]) != 3 || payloads[0][0] != 1 || payloads[0][2] != 3 || len(payloads[1]) != 1 || payloads[1][0] != 10 {
+		t.Fatal("payloads must be [[1 2 3] [10]], got", payloads)
+	}
+
+	dispatcher.Tick()
+	if len(values) != 3 || len(payloads[2]) != 1 || payloads[2][0] != 100 {
+		t.Fatal("payloads must be [[1 2 3] [10] [100]], got", payloads)
+	}
+}
+
+func TestPacingListenerTick(t *testing.T) {
+	testErr := errors.New("test error")
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	value := 0
+
+	sub, _ := dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		value++
+		return testErr
+	}, WithCoalesce(), WithTimes(2))
+
+	if dispatcher.Tick() != nil {
+		t.Fatal("there must no error")
+	}
+	for i := 1; i <= 3; i++ {
+		dispatcher.Dispatch(testEventID{1, testEV(i)}, nil)
+	}
+
+	// 监听者的错误以缓冲阶段返回，接收次数耗尽后被移除
+	err := dispatcher.Tick()
+	var dispatchErrs *DispatchErrors[testET, testEV, testLID]
+	if !errors.As(err, &dispatchErrs) || dispatchErrs.Len() != 2 || dispatchErrs.Errors()[0].Stage != DispatchStageBuffered || !errors.Is(err, testErr) {
+		t.Fatal("error must be buffered test error")
+	}
+	if value != 2 || sub.Active() || sub.Stats().Delivered != 2 || sub.Stats().Errors != 2 {
+		t.Fatal("value must be", 2)
+	}
+	if dispatcher.Tick() != nil || value != 2 {
+		t.Fatal("value must be", 2)
+	}
+
+	// 移除监听者后丢弃缓冲的事件
+	sub, _ = dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		value++
+		return nil
+	}, WithDebounce(time.Nanosecond))
+	dispatcher.Dispatch(testEventID{1, 1}, nil)
+	sub.Unsubscribe()
+	time.Sleep(time.Millisecond)
+	if dispatcher.Tick() != nil || value != 2 {
+		t.Fatal("value must be", 2)
+	}
+
+	concurrent := NewConcurrentDispatcher[testET, testEV, testLID]()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("ConcurrentDispatcher must not support pacing")
+			}
+		}()
+		concurrent.AddKindListener(1, 1, func(e testEvent) error { return nil }, WithThrottle(time.Second))
+	}()
+}
@@ -0,0 +1,105 @@
+package gevent
+
+// Responder 应答者回调
+// 接收事件并返回应答结果，返回监听者产生的错误时，结果不会被收集
+type Responder[EventKind, EventValue comparable, Result any] func(Event[EventKind, EventValue]) (Result, error)
+
+// Reducer 应答结果的归并函数，将 reply 归并到 acc 并返回归并后的结果
+type Reducer[Result any] func(acc Result, reply Result) Result
+
+// Query 请求/应答式的事件派发器
+// 在 Dispatcher 的基础上，应答者可以为事件返回 Result 类型的结果，由 Ask 按照接收事件的顺序收集所有结果，
+// 或由 Fold 通过归并函数将所有结果归并为一个，适用于收集加成、否决投票、计算修正值等场景
+// 应答者返回 ErrStopPropagation、ErrRemAfterDispatch 等派发器专用的 error 时，结果仍会被收集
+// 与 Dispatcher 相同，不能被多个 goroutine 同时调用
+type Query[EventKind, EventValue, ListenerID comparable, Result any] struct {
+	dispatcher *Dispatcher[EventKind, EventValue, ListenerID] // 底层的事件派发器
+	collect    func(Result)                                   // 当前请求的结果收集函数，不在请求中时为空
+}
+
+func NewQuery[EventKind, EventValue, ListenerID comparable, Result any](opts ...DispatcherOption[EventKind, EventValue, ListenerID]) *Query[EventKind, EventValue, ListenerID, Result] {
+	return &Query[EventKind, EventValue, ListenerID, Result]{
+		dispatcher: NewDispatcher(opts...),
+	}
+}
+
+// Dispatcher 返回底层的事件派发器
+// 通过底层派发器直接派发事件时，应答者的结果被丢弃
+func (q *Query[EventKind, EventValue, ListenerID, Result]) Dispatcher() *Dispatcher[EventKind, EventValue, ListenerID] {
+	return q.dispatcher
+}
+
+// AddKindResponder 添加事件类型应答者
+// 添加成功时返回对应的订阅
+func (q *Query[EventKind, EventValue, ListenerID, Result]) AddKindResponder(evtKind EventKind, lID ListenerID, responder Responder[EventKind, EventValue, Result], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return q.dispatcher.AddKindListener(evtKind, lID, q.callback(responder), opts...)
+}
+
+// AddValueResponder 添加值类型应答者
+// 添加成功时返回对应的订阅
+func (q *Query[EventKind, EventValue, ListenerID, Result]) AddValueResponder(evtId EventID[EventKind, EventValue], lID ListenerID, responder Responder[EventKind, EventValue, Result], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return q.dispatcher.AddValueListener(evtId, lID, q.callback(responder), opts...)
+}
+
+// RemKindResponder 移除事件类型应答者
+func (q *Query[EventKind, EventValue, ListenerID, Result]) RemKindResponder(evtKind EventKind, lID ListenerID) bool {
+	return q.dispatcher.RemKindListener(evtKind, lID)
+}
+
+// RemValueResponder 移除值类型应答者
+func (q *Query[EventKind, EventValue, ListenerID, Result]) RemValueResponder(evtId EventID[EventKind, EventValue], lID ListenerID) bool {
+	return q.dispatcher.RemValueListener(evtId, lID)
+}
+
+// Clear 清理状态，移除所有应答者
+func (q *Query[EventKind, EventValue, ListenerID, Result]) Clear() {
+	q.dispatcher.Clear()
+}
+
+// Ask 构造事件，派发给 evtID 指定的应答者们，按照应答者接收事件的顺序返回所有结果
+// 应答者们返回的错误通过 *DispatchErrors 返回，此时仍返回已收集的结果
+func (q *Query[EventKind, EventValue, ListenerID, Result]) Ask(evtId EventID[EventKind, EventValue], generator interface{}, param ...interface{}) ([]Result, error) {
+	var replies []Result
+	err := q.request(func(reply Result) {
+		replies = append(replies, reply)
+	}, evtId, generator, param)
+	return replies, err
+}
+
+// Fold 构造事件，派发给 evtID 指定的应答者们，以 initial 为初始值，按照应答者接收事件的顺序通过 reducer 归并所有结果
+// 应答者们返回的错误通过 *DispatchErrors 返回，此时仍返回已归并的结果
+func (q *Query[EventKind, EventValue, ListenerID, Result]) Fold(evtId EventID[EventKind, EventValue], initial Result, reducer Reducer[Result], generator interface{}, param ...interface{}) (Result, error) {
+	if reducer == nil {
+		panic("query reducer nil")
+	}
+	acc := initial
+	err := q.request(func(reply Result) {
+		acc = reducer(acc, reply)
+	}, evtId, generator, param)
+	return acc, err
+}
+
+// request 以 collect 收集结果，派发事件
+// 应答过程中嵌套的请求使用各自的收集函数，完成后恢复外层请求的收集函数
+func (q *Query[EventKind, EventValue, ListenerID, Result]) request(collect func(Result), evtId EventID[EventKind, EventValue], generator interface{}, param []interface{}) error {
+	prev := q.collect
+	q.collect = collect
+	defer func() {
+		q.collect = prev
+	}()
+	return q.dispatcher.Dispatch(evtId, generator, param...)
+}
+
+// callback 将应答者转换为监听者回调，结果交由当前请求的收集函数收集
+func (q *Query[EventKind, EventValue, ListenerID, Result]) callback(responder Responder[EventKind, EventValue, Result]) ListenerCallback[EventKind, EventValue] {
+	if responder == nil {
+		panic("query responder nil")
+	}
+	return func(evt Event[EventKind, EventValue]) error {
+		reply, err := responder(evt)
+		if !isListenerError(err) && q.collect != nil {
+			q.collect(reply)
+		}
+		return err
+	}
+}
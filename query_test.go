@@ -0,0 +1,105 @@
+package gevent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	query := NewQuery[testET, testEV, testLID, int]()
+
+	query.AddKindResponder(1, 1, func(e testEvent) (int, error) {
+		return e.Param().(int) * 2, nil
+	})
+	query.AddKindResponder(1, 2, func(e testEvent) (int, error) {
+		return 10, nil
+	}, WithPriority(1))
+	query.AddValueResponder(testEventID{1, 1}, 3, func(e testEvent) (int, error) {
+		return 100, nil
+	})
+
+	replies, err := query.Ask(testEventID{1, 1}, nil, 3)
+	if err != nil {
+		t.Fatal("there must no error")
+	}
+	if len(replies) != 3 || replies[0] != 10 || replies[1] != 6 || replies[2] != 100 {
+		t.Fatal("replies must be [10 6 100], got", replies)
+	}
+
+	sum, err := query.Fold(testEventID{1, 2}, 1, func(acc, reply int) int { return acc + reply }, nil, 5)
+	if err != nil || sum != 21 {
+		t.Fatal("sum must be", 21)
+	}
+
+	if replies, _ := query.Ask(testEventID{2, 1}, nil); len(replies) != 0 {
+		t.Fatal("replies must be empty")
+	}
+
+	// 直接通过底层派发器派发时，结果被丢弃
+	if err := query.Dispatcher().Dispatch(testEventID{1, 2}, nil, 1); err != nil {
+		t.Fatal("there must no error")
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	testErr := errors.New("test error")
+	query := NewQuery[testET, testEV, testLID, bool]()
+
+	query.AddKindResponder(1, 1, func(e testEvent) (bool, error) {
+		return false, testErr
+	})
+	query.AddKindResponder(1, 2, func(e testEvent) (bool, error) {
+		// 否决，结果仍被收集，后续应答者不再接收事件
+		return false, ErrStopPropagation
+	})
+	query.AddKindResponder(1, 3, func(e testEvent) (bool, error) {
+		return true, nil
+	})
+
+	replies, err := query.Ask(testEventID{1, 1}, nil)
+	if !errors.Is(err, testErr) {
+		t.Fatal("error must be test error")
+	}
+	if len(replies) != 1 || replies[0] {
+		t.Fatal("replies must be [false], got", replies)
+	}
+
+	allowed, _ := query.Fold(testEventID{1, 1}, true, func(acc, reply bool) bool { return acc && reply }, nil)
+	if allowed {
+		t.Fatal("query must be vetoed")
+	}
+	if !query.RemKindResponder(1, 2) {
+		t.Fatal("responder must be removed")
+	}
+	allowed, _ = query.Fold(testEventID{1, 1}, true, func(acc, reply bool) bool { return acc && reply }, nil)
+	if !allowed {
+		t.Fatal("query must be allowed")
+	}
+}
+
+func TestNestedQuery(t *testing.T) {
+	query := NewQuery[testET, testEV, testLID, int]()
+
+	query.AddKindResponder(1, 1, func(e testEvent) (int, error) {
+		// 嵌套的请求使用各自的收集函数
+		replies, err := query.Ask(testEventID{2, 1}, nil)
+		if err != nil || len(replies) != 2 {
+			t.Fatal("nested replies must be [2 3], got", replies)
+		}
+		return replies[0] + replies[1], nil
+	})
+	query.AddKindResponder(1, 2, func(e testEvent) (int, error) {
+		return 1, nil
+	})
+	query.AddKindResponder(2, 3, func(e testEvent) (int, error) {
+		return 2, nil
+	})
+	query.AddKindResponder(2, 4, func(e testEvent) (int, error) {
+		return 3, nil
+	})
+
+	replies, err := query.Ask(testEventID{1, 1}, nil)
+	if err != nil || len(replies) != 2 || replies[0] != 5 || replies[1] != 1 {
+		t.Fatal("replies must be [5 1], got", replies)
+	}
+}
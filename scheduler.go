@@ -0,0 +1,333 @@
+package gevent
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrSchedulerManual 手动模式的调度器不能自动运行
+	ErrSchedulerManual = errors.New("scheduler is in manual mode")
+
+	// ErrSchedulerRunning 调度器已在运行
+	ErrSchedulerRunning = errors.New("scheduler already running")
+)
+
+// EventDispatcher 事件派发接口
+// Dispatcher、ConcurrentDispatcher 等派发器均实现了该接口
+type EventDispatcher[EventKind, EventValue comparable] interface {
+	Dispatch(evtId EventID[EventKind, EventValue], generator interface{}, param ...interface{}) error
+}
+
+// SchedulerOptions 调度器选项
+type SchedulerOptions[EventKind, EventValue comparable] struct {
+	Clock        Clock                                    // 实时模式使用的时钟，为空时使用系统时钟；手动模式下无效
+	ErrorHandler EventErrorHandler[EventKind, EventValue] // 处理派发到期事件时的错误，为空时丢弃错误
+}
+
+// ScheduledEvent 已调度的事件，可用于取消派发
+type ScheduledEvent[EventKind, EventValue comparable] struct {
+	scheduler *Scheduler[EventKind, EventValue] // 所属的调度器
+	evtId     EventID[EventKind, EventValue]    // 事件ID
+	generator interface{}                       // 事件产生者
+	param     []interface{}                     // 参数
+	when      time.Time                         // 派发时间
+	at        time.Time                         // 调度时的时间
+	seq       uint64                            // 调度序号，派发时间相同时先调度的先派发
+	index     int                               // 在调度队列中的位置，不在队列中时为 -1
+	deferred  bool                              // 是否在本次派发中暂缓，等待派发结束后重新加入调度队列
+}
+
+// EventID 返回事件ID
+func (e *ScheduledEvent[EventKind, EventValue]) EventID() EventID[EventKind, EventValue] {
+	return e.evtId
+}
+
+// When 返回事件的派发时间
+func (e *ScheduledEvent[EventKind, EventValue]) When() time.Time {
+	return e.when
+}
+
+// Cancel 取消派发，返回是否在派发前取消成功
+func (e *ScheduledEvent[EventKind, EventValue]) Cancel() bool {
+	return e.scheduler.cancel(e)
+}
+
+// scheduleQueue 按派发时间排列的调度队列，实现 heap.Interface
+type scheduleQueue[EventKind, EventValue comparable] []*ScheduledEvent[EventKind, EventValue]
+
+func (q scheduleQueue[EventKind, EventValue]) Len() int { return len(q) }
+
+func (q scheduleQueue[EventKind, EventValue]) Less(i, j int) bool {
+	if q[i].when.Equal(q[j].when) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].when.Before(q[j].when)
+}
+
+func (q scheduleQueue[EventKind, EventValue]) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *scheduleQueue[EventKind, EventValue]) Push(x interface{}) {
+	e := x.(*ScheduledEvent[EventKind, EventValue])
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *scheduleQueue[EventKind, EventValue]) Pop() interface{} {
+	old := *q
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*q = old[:n-1]
+	return e
+}
+
+// Scheduler 事件调度器
+// 在指定的延迟后或指定的时间，将事件派发给目标派发器，支持两种模式：
+// 1. 实时模式，通过 NewScheduler 创建，按照时钟判断事件是否到期，由 Poll 或 Run 派发到期事件；
+// 2. 手动模式，通过 NewManualScheduler 创建，时间只在调用 Advance 时推进，适用于确定性的游戏循环及测试。
+// 调度器本身可被多个 goroutine 同时调用，但到期事件在调用 Poll、Advance 或 Run 的 goroutine 中派发，
+// 目标派发器为 Dispatcher 等非并发安全的派发器时，应在其所在的 goroutine 中调用 Poll 或 Advance
+type Scheduler[EventKind, EventValue comparable] struct {
+	target  EventDispatcher[EventKind, EventValue]  // 目标派发器
+	options SchedulerOptions[EventKind, EventValue] // 选项
+	manual  bool                                    // 是否为手动模式
+	mtx     sync.Mutex                              // 保护以下字段
+	now     time.Time                               // 手动模式下的当前时间
+	queue   scheduleQueue[EventKind, EventValue]    // 调度队列
+	seq     uint64                                  // 下一个调度序号
+	running bool                                    // 是否正在 Run
+	wake    chan struct{}                           // 调度队列变化时唤醒 Run
+}
+
+// NewScheduler 创建实时模式的调度器
+func NewScheduler[EventKind, EventValue comparable](target EventDispatcher[EventKind, EventValue], options SchedulerOptions[EventKind, EventValue]) *Scheduler[EventKind, EventValue] {
+	if target == nil {
+		panic("scheduler target nil")
+	}
+	if options.Clock == nil {
+		options.Clock = systemClock{}
+	}
+	return &Scheduler[EventKind, EventValue]{
+		target:  target,
+		options: options,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// NewManualScheduler 创建手动模式的调度器，start 为起始时间
+func NewManualScheduler[EventKind, EventValue comparable](target EventDispatcher[EventKind, EventValue], start time.Time, options SchedulerOptions[EventKind, EventValue]) *Scheduler[EventKind, EventValue] {
+	if target == nil {
+		panic("scheduler target nil")
+	}
+	options.Clock = nil
+	return &Scheduler[EventKind, EventValue]{
+		target:  target,
+		options: options,
+		manual:  true,
+		now:     start,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Now 返回调度器的当前时间
+func (s *Scheduler[EventKind, EventValue]) Now() time.Time {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.nowLocked()
+}
+
+// nowLocked 返回调度器的当前时间，调用时需持有锁
+func (s *Scheduler[EventKind, EventValue]) nowLocked() time.Time {
+	if s.manual {
+		return s.now
+	}
+	return s.options.Clock.Now()
+}
+
+// Pending 返回等待派发的事件数量
+func (s *Scheduler[EventKind, EventValue]) Pending() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return len(s.queue)
+}
+
+// DispatchAfter 在 d 时长后派发事件，返回已调度的事件
+func (s *Scheduler[EventKind, EventValue]) DispatchAfter(d time.Duration, evtId EventID[EventKind, EventValue], generator interface{}, param ...interface{}) *ScheduledEvent[EventKind, EventValue] {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	now := s.nowLocked()
+	return s.scheduleLocked(now.Add(d), now, evtId, generator, param)
+}
+
+// DispatchAt 在 t 时刻派发事件，返回已调度的事件
+// t 早于当前时间时，在下一次派发到期事件时派发
+func (s *Scheduler[EventKind, EventValue]) DispatchAt(t time.Time, evtId EventID[EventKind, EventValue], generator interface{}, param ...interface{}) *ScheduledEvent[EventKind, EventValue] {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.scheduleLocked(t, s.nowLocked(), evtId, generator, param)
+}
+
+// scheduleLocked 将事件加入调度队列，调用时需持有锁
+func (s *Scheduler[EventKind, EventValue]) scheduleLocked(when, now time.Time, evtId EventID[EventKind, EventValue], generator interface{}, param []interface{}) *ScheduledEvent[EventKind, EventValue] {
+	e := &ScheduledEvent[EventKind, EventValue]{
+		scheduler: s,
+		evtId:     evtId,
+		generator: generator,
+		param:     param,
+		when:      when,
+		at:        now,
+		seq:       s.seq,
+	}
+	s.seq++
+	heap.Push(&s.queue, e)
+	if e.index == 0 {
+		s.notify()
+	}
+	return e
+}
+
+// cancel 将事件移出调度队列
+func (s *Scheduler[EventKind, EventValue]) cancel(e *ScheduledEvent[EventKind, EventValue]) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if e.deferred {
+		// 暂缓的事件不会再重新加入调度队列
+		e.deferred = false
+		return true
+	}
+	if e.index < 0 {
+		return false
+	}
+	heap.Remove(&s.queue, e.index)
+	s.notify()
+	return true
+}
+
+// notify 唤醒 Run 重新计算下一个事件的派发时间
+func (s *Scheduler[EventKind, EventValue]) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Poll 派发所有已到期的事件，返回派发的事件数量，仅用于实时模式
+// 派发过程中调度的、调度时即已到期的事件，留待下一次 Poll 派发，避免无限循环
+func (s *Scheduler[EventKind, EventValue]) Poll() int {
+	if s.manual {
+		panic("poll on manual scheduler")
+	}
+	s.mtx.Lock()
+	now := s.nowLocked()
+	s.mtx.Unlock()
+	return s.fire(now, false)
+}
+
+// Advance 将手动模式的时间推进 d，并按时间顺序派发期间到期的事件，返回派发的事件数量
+// 派发每个事件时，调度器的当前时间为该事件的派发时间，因此派发过程中以 DispatchAfter 调度的事件基于该时间计算
+// 派发过程中调度的、调度时即已到期的事件，留待下一次 Advance 派发，避免无限循环
+func (s *Scheduler[EventKind, EventValue]) Advance(d time.Duration) int {
+	if !s.manual {
+		panic("advance on real-time scheduler")
+	}
+	s.mtx.Lock()
+	deadline := s.now.Add(d)
+	s.mtx.Unlock()
+	return s.fire(deadline, true)
+}
+
+// fire 按时间顺序派发 deadline 及之前到期的事件
+// advance 为 true 时，派发每个事件前将手动模式的时间推进到该事件的派发时间，最终推进到 deadline
+func (s *Scheduler[EventKind, EventValue]) fire(deadline time.Time, advance bool) int {
+	s.mtx.Lock()
+	startSeq := s.seq
+	var deferred []*ScheduledEvent[EventKind, EventValue]
+	n := 0
+	for len(s.queue) > 0 && !s.queue[0].when.After(deadline) {
+		e := heap.Pop(&s.queue).(*ScheduledEvent[EventKind, EventValue])
+		if e.seq >= startSeq && !e.when.After(e.at) {
+			// 本次派发过程中调度的、调度时即已到期的事件，留待下一次派发
+			e.deferred = true
+			deferred = append(deferred, e)
+			continue
+		}
+		if advance && e.when.After(s.now) {
+			s.now = e.when
+		}
+		s.mtx.Unlock()
+
+		n++
+		if err := s.target.Dispatch(e.evtId, e.generator, e.param...); err != nil && s.options.ErrorHandler != nil {
+			s.options.ErrorHandler(e.evtId, err)
+		}
+
+		s.mtx.Lock()
+	}
+	for _, e := range deferred {
+		if e.deferred {
+			e.deferred = false
+			heap.Push(&s.queue, e)
+		}
+	}
+	if advance && deadline.After(s.now) {
+		s.now = deadline
+	}
+	s.mtx.Unlock()
+	return n
+}
+
+// Run 在当前 goroutine 中按照时钟持续派发到期的事件，直到 ctx 结束，仅用于实时模式
+// 目标派发器需支持在该 goroutine 中派发，如 ConcurrentDispatcher
+func (s *Scheduler[EventKind, EventValue]) Run(ctx context.Context) error {
+	if s.manual {
+		return ErrSchedulerManual
+	}
+	s.mtx.Lock()
+	if s.running {
+		s.mtx.Unlock()
+		return ErrSchedulerRunning
+	}
+	s.running = true
+	s.mtx.Unlock()
+	defer func() {
+		s.mtx.Lock()
+		s.running = false
+		s.mtx.Unlock()
+	}()
+
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		s.Poll()
+
+		s.mtx.Lock()
+		wait := time.Hour
+		if len(s.queue) > 0 {
+			wait = s.queue[0].when.Sub(s.nowLocked())
+		}
+		s.mtx.Unlock()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.wake:
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package gevent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestManualScheduler(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	start := time.Unix(1000, 0)
+	scheduler := NewManualScheduler[testET, testEV](dispatcher, start, SchedulerOptions[testET, testEV]{})
+	var values []testEV
+	var times []time.Duration
+
+	dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		values = append(values, e.EventID().Value)
+		times = append(times, scheduler.Now().Sub(start))
+		if e.EventID().Value == 2 {
+			// 派发过程中调度的事件基于该事件的派发时间计算
+			scheduler.DispatchAfter(time.Second, testEventID{1, 4}, nil)
+		}
+		return nil
+	})
+
+	scheduler.DispatchAfter(3*time.Second, testEventID{1, 3}, nil)
+	scheduler.DispatchAt(start.Add(2*time.Second), testEventID{1, 2}, nil)
+	scheduler.DispatchAfter(time.Second, testEventID{1, 1}, nil)
+	canceled := scheduler.DispatchAfter(time.Second, testEventID{1, 5}, nil)
+	if scheduler.Pending() != 4 {
+		t.Fatal("pending count must be", 4)
+	}
+	if !canceled.Cancel() || canceled.Cancel() {
+		t.Fatal("event must be canceled only once")
+	}
+
+	if n := scheduler.Advance(500 * time.Millisecond); n != 0 {
+		t.Fatal("dispatched count must be", 0)
+	}
+	if n := scheduler.Advance(5 * time.Second); n != 4 {
+		t.Fatal("dispatched count must be", 4)
+	}
+	if len(values) != 4 || values[0] != 1 || values[1] != 2 || values[2] != 3 || values[3] != 4 {
+		t.Fatal("values must be [1 2 3 4], got", values)
+	}
+	// 事件 3 与事件 4 的派发时间相同，先调度的先派发
+	if times[0] != time.Second || times[1] != 2*time.Second || times[2] != 3*time.Second || times[3] != 3*time.Second {
+		t.Fatal("times must be [1s 2s 3s 3s], got", times)
+	}
+	if scheduler.Now() != start.Add(5500*time.Millisecond) {
+		t.Fatal("now must be", start.Add(5500*time.Millisecond))
+	}
+}
+
+func TestManualSchedulerZeroDelay(t *testing.T) {
+	testErr := errors.New("test error")
+	var handled int
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	scheduler := NewManualScheduler[testET, testEV](dispatcher, time.Unix(0, 0), SchedulerOptions[testET, testEV]{
+		ErrorHandler: func(evtId testEventID, err error) {
+			if !errors.Is(err, testErr) {
+				t.Fatal("error must be test error")
+			}
+			handled++
+		},
+	})
+	value := 0
+
+	dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		value++
+		// 派发过程中调度的、调度时即已到期的事件留待下一次 Advance
+		scheduler.DispatchAfter(0, testEventID{1, 1}, nil)
+		return testErr
+	})
+
+	scheduler.DispatchAfter(0, testEventID{1, 1}, nil)
+	for i := 1; i <= 3; i++ {
+		if n := scheduler.Advance(0); n != 1 {
+			t.Fatal("dispatched count must be", 1)
+		}
+		if value != i || handled != i {
+			t.Fatal("value must be", i)
+		}
+	}
+	if scheduler.Pending() != 1 {
+		t.Fatal("pending count must be", 1)
+	}
+}
+
+func TestManualSchedulerCancelDeferred(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	scheduler := NewManualScheduler[testET, testEV](dispatcher, time.Unix(0, 0), SchedulerOptions[testET, testEV]{})
+	var values []testEV
+	var deferred *ScheduledEvent[testET, testEV]
+	canceled := false
+
+	dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		values = append(values, e.EventID().Value)
+		switch e.EventID().Value {
+		case 1:
+			// 调度时即已到期，留待下一次 Advance
+			deferred = scheduler.DispatchAfter(0, testEventID{1, 3}, nil)
+		case 2:
+			// 同一次 Advance 中取消暂缓的事件
+			canceled = deferred.Cancel()
+		}
+		return nil
+	})
+
+	scheduler.DispatchAfter(time.Second, testEventID{1, 1}, nil)
+	scheduler.DispatchAfter(2*time.Second, testEventID{1, 2}, nil)
+	if n := scheduler.Advance(2 * time.Second); n != 2 {
+		t.Fatal("dispatched count must be", 2)
+	}
+	if !canceled || deferred.Cancel() {
+		t.Fatal("deferred event must be canceled only once")
+	}
+	if n := scheduler.Advance(time.Second); n != 0 || scheduler.Pending() != 0 {
+		t.Fatal("dispatched count must be", 0)
+	}
+	if len(values) != 2 {
+		t.Fatal("values must be [1 2], got", values)
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	clock := newTestClock()
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	scheduler := NewScheduler[testET, testEV](dispatcher, SchedulerOptions[testET, testEV]{Clock: clock})
+	value := 0
+
+	dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		value += int(e.EventID().Value)
+		return nil
+	})
+
+	scheduler.DispatchAfter(time.Second, testEventID{1, 1}, nil)
+	scheduler.DispatchAfter(2*time.Second, testEventID{1, 10}, nil)
+	if scheduler.Poll() != 0 || value != 0 {
+		t.Fatal("value must be", 0)
+	}
+	clock.Advance(time.Second)
+	if scheduler.Poll() != 1 || value != 1 {
+		t.Fatal("value must be", 1)
+	}
+	clock.Advance(time.Hour)
+	if scheduler.Poll() != 1 || value != 11 {
+		t.Fatal("value must be", 11)
+	}
+}
+
+func TestSchedulerRun(t *testing.T) {
+	dispatcher := NewConcurrentDispatcher[testET, testEV, testLID]()
+	scheduler := NewScheduler[testET, testEV](dispatcher, SchedulerOptions[testET, testEV]{})
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	var values []testEV
+
+	wg.Add(2)
+	dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		mtx.Lock()
+		values = append(values, e.EventID().Value)
+		mtx.Unlock()
+		wg.Done()
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- scheduler.Run(ctx)
+	}()
+
+	scheduler.DispatchAfter(20*time.Millisecond, testEventID{1, 2}, nil)
+	scheduler.DispatchAfter(time.Hour, testEventID{1, 3}, nil).Cancel()
+	scheduler.DispatchAfter(time.Millisecond, testEventID{1, 1}, nil)
+	wg.Wait()
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatal("error must be", context.Canceled)
+	}
+	if len(values) != 2 || values[0] != 1 || values[1] != 2 {
+		t.Fatal("values must be [1 2], got", values)
+	}
+
+	manual := NewManualScheduler[testET, testEV](dispatcher, time.Now(), SchedulerOptions[testET, testEV]{})
+	if manual.Run(context.Background()) != ErrSchedulerManual {
+		t.Fatal("error must be", ErrSchedulerManual)
+	}
+}
@@ -0,0 +1,123 @@
+package gevent
+
+import "sort"
+
+// stickyEvent 保留的粘性事件
+type stickyEvent[EventKind, EventValue comparable] struct {
+	evt Event[EventKind, EventValue] // 事件
+	seq uint64                       // 保留序号，用于按照派发顺序回放
+}
+
+// DispatchSticky 构造事件，保留为该事件ID的粘性事件后，派发给 evtID 指定的监听者们
+// 每个事件ID只保留最后一次的粘性事件；之后添加的类型监听者会立即接收该类型的所有粘性事件，
+// 值类型监听者会立即接收对应事件ID的粘性事件，适用于"配置已加载"等表示状态的事件
+// 回放时监听者返回的错误按照派发器的错误处理策略处理，未被 ErrorHandler 处理的错误通过 Subscription.ReplayError 返回
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) DispatchSticky(evtId EventID[EventKind, EventValue], generator interface{}, param ...interface{}) error {
+	evt := Event[EventKind, EventValue]{
+		eventID:   evtId,
+		generator: generator,
+		current:   evtId.Kind,
+	}
+	if len(param) > 0 {
+		evt.param = param[0]
+	}
+	if d.sticky == nil {
+		d.sticky = map[EventKind]map[EventValue]*stickyEvent[EventKind, EventValue]{}
+	}
+	events := d.sticky[evtId.Kind]
+	if events == nil {
+		events = map[EventValue]*stickyEvent[EventKind, EventValue]{}
+		d.sticky[evtId.Kind] = events
+	}
+	events[evtId.Value] = &stickyEvent[EventKind, EventValue]{evt: evt, seq: d.stickySeq}
+	d.stickySeq++
+	return d.Dispatch(evtId, generator, param...)
+}
+
+// StickyEvent 返回事件ID对应的粘性事件
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) StickyEvent(evtId EventID[EventKind, EventValue]) (Event[EventKind, EventValue], bool) {
+	if se := d.sticky[evtId.Kind][evtId.Value]; se != nil {
+		return se.evt, true
+	}
+	return Event[EventKind, EventValue]{}, false
+}
+
+// StickyEvents 返回事件类型的所有粘性事件，按照派发顺序排列
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) StickyEvents(evtKind EventKind) []Event[EventKind, EventValue] {
+	events := d.sticky[evtKind]
+	if len(events) == 0 {
+		return nil
+	}
+	stickies := make([]*stickyEvent[EventKind, EventValue], 0, len(events))
+	for _, se := range events {
+		stickies = append(stickies, se)
+	}
+	sort.Slice(stickies, func(i, j int) bool { return stickies[i].seq < stickies[j].seq })
+	evts := make([]Event[EventKind, EventValue], len(stickies))
+	for i, se := range stickies {
+		evts[i] = se.evt
+	}
+	return evts
+}
+
+// RemoveSticky 移除事件ID对应的粘性事件，返回是否移除成功
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) RemoveSticky(evtId EventID[EventKind, EventValue]) bool {
+	events := d.sticky[evtId.Kind]
+	if _, ok := events[evtId.Value]; !ok {
+		return false
+	}
+	delete(events, evtId.Value)
+	if len(events) == 0 {
+		delete(d.sticky, evtId.Kind)
+	}
+	return true
+}
+
+// ClearSticky 移除所有粘性事件
+// Clear 只移除监听者，不会移除粘性事件
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) ClearSticky() {
+	d.sticky = nil
+}
+
+// replaySticky 向新添加的类型监听者或值类型监听者回放匹配的粘性事件
+func (d *Dispatcher[EventKind, EventValue, ListenerID]) replaySticky(l *listener[EventKind, EventValue, ListenerID]) {
+	if d.sticky == nil {
+		return
+	}
+	var evts []Event[EventKind, EventValue]
+	switch l.reg.Scope {
+	case ListenerScopeKind:
+		evts = d.StickyEvents(l.reg.EventID.Kind)
+	case ListenerScopeValue:
+		if evt, ok := d.StickyEvent(l.reg.EventID); ok {
+			evts = []Event[EventKind, EventValue]{evt}
+		}
+	}
+
+	opts := &d.options
+	stage := DispatchStageKind
+	if l.reg.Scope == ListenerScopeValue {
+		stage = DispatchStageValue
+	}
+	state := dispatchContinue
+	var errs []*DispatchError[EventKind, EventValue, ListenerID]
+	for _, evt := range evts {
+		if state != dispatchContinue || l.callback == nil || l.pendingRem || l.expired() {
+			break
+		}
+		evt.replayed = true
+		accepted, err := l.dispatch(evt, opts)
+		if isListenerError(err) {
+			errs, state = opts.onError(errs, &DispatchError[EventKind, EventValue, ListenerID]{
+				EventID:    evt.eventID,
+				Stage:      stage,
+				ListenerID: l.id,
+				Err:        err,
+			})
+		}
+		if ((accepted && l.consume()) || err == ErrRemAfterDispatch) && l.callback != nil && !l.pendingRem {
+			d.remListener(l.reg)
+		}
+	}
+	l.replayErr = newDispatchErrors(errs)
+}
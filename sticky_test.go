@@ -0,0 +1,132 @@
+package gevent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStickyEvent(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	var params []interface{}
+
+	dispatcher.DispatchSticky(testEventID{1, 2}, nil, "b")
+	dispatcher.DispatchSticky(testEventID{1, 1}, nil, "a")
+	dispatcher.DispatchSticky(testEventID{1, 2}, nil, "c")
+	dispatcher.Dispatch(testEventID{1, 3}, nil, "d")
+	dispatcher.DispatchSticky(testEventID{2, 1}, nil, "e")
+
+	if evt, ok := dispatcher.StickyEvent(testEventID{1, 2}); !ok || evt.Param() != "c" {
+		t.Fatal("sticky event param must be", "c")
+	}
+	if _, ok := dispatcher.StickyEvent(testEventID{1, 3}); ok {
+		t.Fatal("event must not be sticky")
+	}
+
+	// 类型监听者按照派发顺序接收该类型的所有粘性事件
+	dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		params = append(params, e.Param())
+		return nil
+	})
+	if len(params) != 2 || params[0] != "a" || params[1] != "c" {
+		t.Fatal("params must be [a c], got", params)
+	}
+
+	// 值类型监听者只接收对应事件ID的粘性事件
+	params = nil
+	dispatcher.AddValueListener(testEventID{1, 2}, 2, func(e testEvent) error {
+		params = append(params, e.Param())
+		return nil
+	})
+	if len(params) != 1 || params[0] != "c" {
+		t.Fatal("params must be [c], got", params)
+	}
+
+	// 只接收一次的监听者接收粘性事件后即被移除
+	sub, _ := dispatcher.AddKindListener(2, 3, func(e testEvent) error { return nil }, WithOnce())
+	if sub.Active() || sub.Stats().Delivered != 1 {
+		t.Fatal("listener must be removed after replay")
+	}
+
+	if !dispatcher.RemoveSticky(testEventID{1, 1}) || dispatcher.RemoveSticky(testEventID{1, 1}) {
+		t.Fatal("sticky event must be removed only once")
+	}
+	if evts := dispatcher.StickyEvents(1); len(evts) != 1 || evts[0].EventID() != (testEventID{1, 2}) {
+		t.Fatal("sticky events must be [{1 2}]")
+	}
+	dispatcher.ClearSticky()
+	if dispatcher.StickyEvents(1) != nil || dispatcher.StickyEvents(2) != nil {
+		t.Fatal("sticky events must be cleared")
+	}
+}
+
+func TestStickyEventOnDispatching(t *testing.T) {
+	testErr := errors.New("test error")
+	var handled int
+	dispatcher := NewDispatcher[testET, testEV, testLID](WithErrorHandler[testET, testEV, testLID](func(evtId testEventID, lID testLID, err error) {
+		if !errors.Is(err, testErr) || lID != 2 {
+			t.Fatal("error must be test error of listener", 2)
+		}
+		handled++
+	}))
+	var values []testEV
+
+	dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		// 派发过程中添加的监听者在派发完成后接收粘性事件
+		dispatcher.AddKindListener(1, 2, func(e testEvent) error {
+			values = append(values, e.EventID().Value)
+			return testErr
+		})
+		return ErrRemAfterDispatch
+	})
+
+	dispatcher.DispatchSticky(testEventID{1, 1}, nil)
+	if len(values) != 1 || values[0] != 1 || handled != 1 {
+		t.Fatal("values must be [1], got", values)
+	}
+	dispatcher.DispatchSticky(testEventID{1, 2}, nil)
+	if len(values) != 2 || values[1] != 2 || handled != 2 {
+		t.Fatal("values must be [1 2], got", values)
+	}
+}
+
+func TestStickyEventReplayError(t *testing.T) {
+	testErr := errors.New("test error")
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	dispatcher.DispatchSticky(testEventID{1, 1}, nil)
+	dispatcher.DispatchSticky(testEventID{1, 2}, nil)
+
+	// 默认策略下回放的错误通过订阅返回
+	sub, _ := dispatcher.AddKindListener(1, 1, func(e testEvent) error {
+		return testErr
+	})
+	var errs *DispatchErrors[testET, testEV, testLID]
+	if !errors.As(sub.ReplayError(), &errs) || len(errs.Errors()) != 2 || !errors.Is(sub.ReplayError(), testErr) {
+		t.Fatal("replay error must contain 2 test errors")
+	}
+	if errs.Errors()[0].Stage != DispatchStageKind || errs.Errors()[1].EventID.Value != 2 {
+		t.Fatal("replay errors must be in dispatch order")
+	}
+	if sub.Stats().Errors != 2 {
+		t.Fatal("errors must be", 2)
+	}
+
+	// FailFast 策略下遇到第一个错误即停止回放
+	dispatcher = NewDispatcher(WithErrorPolicy[testET, testEV, testLID](ErrorPolicyFailFast))
+	dispatcher.DispatchSticky(testEventID{1, 1}, nil)
+	dispatcher.DispatchSticky(testEventID{1, 2}, nil)
+	sub, _ = dispatcher.AddValueListener(testEventID{1, 1}, 1, func(e testEvent) error {
+		return testErr
+	})
+	if !errors.As(sub.ReplayError(), &errs) || errs.Errors()[0].Stage != DispatchStageValue {
+		t.Fatal("replay error must be of value stage")
+	}
+	sub, _ = dispatcher.AddKindListener(1, 2, func(e testEvent) error {
+		return testErr
+	})
+	if !errors.As(sub.ReplayError(), &errs) || len(errs.Errors()) != 1 || sub.Stats().Delivered != 1 {
+		t.Fatal("replay must stop at first error")
+	}
+	if sub, _ := dispatcher.AddKindListener(1, 3, func(e testEvent) error { return nil }); sub.ReplayError() != nil {
+		t.Fatal("there must no replay error")
+	}
+}
@@ -0,0 +1,126 @@
+package gevent
+
+import "fmt"
+
+// ListenerScope 监听范围
+type ListenerScope int
+
+const (
+	ListenerScopeKind      ListenerScope = iota // 监听某一类型的事件
+	ListenerScopeValue                          // 监听某一事件ID的事件
+	ListenerScopeGlobal                         // 监听所有类型的事件
+	ListenerScopeMultiKind                      // 监听多个类型的事件
+	ListenerScopeCapture                        // 在捕获阶段监听某一类型的事件
+)
+
+func (s ListenerScope) String() string {
+	switch s {
+	case ListenerScopeKind:
+		return "kind"
+	case ListenerScopeValue:
+		return "value"
+	case ListenerScopeGlobal:
+		return "global"
+	case ListenerScopeMultiKind:
+		return "multi-kind"
+	case ListenerScopeCapture:
+		return "capture"
+	default:
+		return fmt.Sprintf("ListenerScope(%d)", int(s))
+	}
+}
+
+// Registration 监听者的注册信息
+type Registration[EventKind, EventValue, ListenerID comparable] struct {
+	Scope      ListenerScope                  // 监听范围
+	EventID    EventID[EventKind, EventValue] // 监听的事件ID，监听范围为 ListenerScopeKind、ListenerScopeCapture 时仅 Kind 有效，为 ListenerScopeGlobal、ListenerScopeMultiKind 时无效
+	ListenerID ListenerID                     // 监听者ID
+}
+
+// subscriptionHandle 订阅对应的监听者
+type subscriptionHandle interface {
+	// isActive 返回监听者是否仍然有效
+	isActive() bool
+
+	// isRegistered 返回监听者是否仍注册在派发器中，已过期但尚未被移除的监听者同样视为已注册
+	isRegistered() bool
+
+	// getStats 返回监听者的统计数据
+	getStats() ListenerStats
+
+	// getReplayError 返回回放粘性事件时产生的错误
+	getReplayError() error
+}
+
+// ListenerStats 监听者的统计数据
+type ListenerStats struct {
+	Delivered   uint64 // 接收事件的次数
+	FilteredOut uint64 // 被过滤器过滤掉的事件数
+	Errors      uint64 // 产生错误的次数，不包括 ErrStopPropagation 和 ErrRemAfterDispatch
+}
+
+// subscriptionOwner 订阅所属的派发器
+type subscriptionOwner[EventKind, EventValue, ListenerID comparable] interface {
+	// unsubscribe 移除订阅对应的监听者
+	unsubscribe(s *Subscription[EventKind, EventValue, ListenerID]) bool
+}
+
+// Subscription 订阅，添加监听者成功时返回
+// 持有订阅即可移除对应的监听者，无须记录监听者的注册信息
+type Subscription[EventKind, EventValue, ListenerID comparable] struct {
+	owner        subscriptionOwner[EventKind, EventValue, ListenerID] // 所属的派发器
+	handle       subscriptionHandle                                   // 对应的监听者
+	registration Registration[EventKind, EventValue, ListenerID]      // 注册信息
+}
+
+func newSubscription[EventKind, EventValue, ListenerID comparable](owner subscriptionOwner[EventKind, EventValue, ListenerID], handle subscriptionHandle, registration Registration[EventKind, EventValue, ListenerID]) *Subscription[EventKind, EventValue, ListenerID] {
+	return &Subscription[EventKind, EventValue, ListenerID]{
+		owner:        owner,
+		handle:       handle,
+		registration: registration,
+	}
+}
+
+// Registration 返回监听者的注册信息
+func (s *Subscription[EventKind, EventValue, ListenerID]) Registration() Registration[EventKind, EventValue, ListenerID] {
+	return s.registration
+}
+
+// Active 返回订阅是否仍然有效
+// 监听者被移除（包括只监听一次的监听者接收事件后、返回 ErrRemAfterDispatch 后）即失效
+func (s *Subscription[EventKind, EventValue, ListenerID]) Active() bool {
+	return s.handle.isActive()
+}
+
+// Stats 返回监听者的统计数据
+// 监听者被移除后，统计数据不再变化
+func (s *Subscription[EventKind, EventValue, ListenerID]) Stats() ListenerStats {
+	return s.handle.getStats()
+}
+
+// ReplayError 返回添加监听者时回放粘性事件所产生的错误，参见 Dispatcher.DispatchSticky
+// 错误处理策略为 ErrorPolicyHandle 且指定了 ErrorHandler 时错误交由其处理，此处返回 nil；
+// 派发过程中添加的监听者在派发完成后才回放，此前同样返回 nil
+func (s *Subscription[EventKind, EventValue, ListenerID]) ReplayError() error {
+	return s.handle.getReplayError()
+}
+
+// Unsubscribe 取消订阅，移除对应的监听者
+// 仅移除本次订阅添加的监听者，不会影响之后使用相同ID添加的监听者
+// 已过期但尚未被移除的监听者同样会被移除
+// 返回是否移除成功，监听者已被移除时返回 false
+func (s *Subscription[EventKind, EventValue, ListenerID]) Unsubscribe() bool {
+	if !s.handle.isRegistered() {
+		return false
+	}
+	return s.owner.unsubscribe(s)
+}
+
+// UnsubscribeAll 取消所有订阅，忽略其中为空的订阅
+func UnsubscribeAll[EventKind, EventValue, ListenerID comparable](subs ...*Subscription[EventKind, EventValue, ListenerID]) {
+	for _, s := range subs {
+		if s != nil {
+			s.Unsubscribe()
+		}
+	}
+}
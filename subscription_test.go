@@ -0,0 +1,166 @@
+package gevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscription(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+	eventVal := testEV(1)
+	value := 0
+
+	add := func(e testEvent) error {
+		value += 1
+		return nil
+	}
+
+	kindSub, ok := dispatcher.AddKindListener(eventType, 1, add)
+	if !ok || kindSub == nil {
+		t.Fatal("add listener must succeed")
+	}
+	if sub, ok := dispatcher.AddKindListener(eventType, 1, add); ok || sub != nil {
+		t.Fatal("repeated listener must not return subscription")
+	}
+	reg := kindSub.Registration()
+	if reg.Scope != ListenerScopeKind || reg.EventID.Kind != eventType || reg.ListenerID != 1 {
+		t.Fatal("registration mismatch", reg)
+	}
+
+	valueSub, _ := dispatcher.AddValueListener(testEventID{eventType, eventVal}, 2, add)
+	if reg := valueSub.Registration(); reg.Scope != ListenerScopeValue || reg.EventID != (testEventID{eventType, eventVal}) {
+		t.Fatal("registration mismatch", reg)
+	}
+	onceSub, _ := dispatcher.AddValueListener(testEventID{eventType, eventVal}, 3, add, WithOnce())
+
+	dispatcher.Dispatch(testEventID{eventType, eventVal}, nil)
+	if value != 3 {
+		t.Fatal("value must be", 3)
+	}
+	if onceSub.Active() {
+		t.Fatal("once subscription must be inactive after dispatch")
+	}
+	if onceSub.Unsubscribe() {
+		t.Fatal("inactive subscription must not unsubscribe")
+	}
+
+	if !kindSub.Unsubscribe() || kindSub.Active() {
+		t.Fatal("kind subscription must be unsubscribed")
+	}
+	// 旧订阅不会影响之后使用相同ID添加的监听者
+	newKindSub, _ := dispatcher.AddKindListener(eventType, 1, add)
+	if kindSub.Unsubscribe() {
+		t.Fatal("unsubscribed subscription must not unsubscribe again")
+	}
+	if !newKindSub.Active() {
+		t.Fatal("new subscription must be active")
+	}
+
+	UnsubscribeAll(newKindSub, valueSub, nil)
+	value = 0
+	dispatcher.Dispatch(testEventID{eventType, eventVal}, nil)
+	if value != 0 {
+		t.Fatal("value must be", 0)
+	}
+}
+
+func TestSubscriptionOnDispatching(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+	value := 0
+
+	add := func(e testEvent) error {
+		value += 1
+		return nil
+	}
+	var pendingSub *Subscription[testET, testEV, testLID]
+	dispatcher.AddKindListener(eventType, 1, func(e testEvent) error {
+		if pendingSub == nil {
+			// 挂起添加的监听者可以通过订阅取消
+			pendingSub, _ = dispatcher.AddKindListener(eventType, 2, add)
+			if !pendingSub.Active() || !pendingSub.Unsubscribe() || pendingSub.Active() {
+				t.Fatal("pending subscription must be unsubscribed")
+			}
+		}
+		return nil
+	})
+	sub, _ := dispatcher.AddKindListener(eventType, 3, func(e testEvent) error {
+		return ErrRemAfterDispatch
+	})
+
+	dispatcher.Dispatch(testEventID{eventType, 1}, nil)
+	dispatcher.Dispatch(testEventID{eventType, 1}, nil)
+	if value != 0 {
+		t.Fatal("value must be", 0)
+	}
+	if sub.Active() {
+		t.Fatal("subscription must be inactive after ErrRemAfterDispatch")
+	}
+}
+
+func TestConcurrentSubscription(t *testing.T) {
+	dispatcher := NewConcurrentDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+	eventVal := testEV(1)
+	value := 0
+
+	add := func(e testEvent) error {
+		value += 1
+		return nil
+	}
+	kindSub, _ := dispatcher.AddKindListener(eventType, 1, add)
+	valueSub, _ := dispatcher.AddValueListener(testEventID{eventType, eventVal}, 2, add)
+	onceSub, _ := dispatcher.AddKindListener(eventType, 3, add, WithOnce())
+
+	dispatcher.Dispatch(testEventID{eventType, eventVal}, nil)
+	if value != 3 {
+		t.Fatal("value must be", 3)
+	}
+	if onceSub.Active() || onceSub.Unsubscribe() {
+		t.Fatal("once subscription must be inactive after dispatch")
+	}
+
+	if !kindSub.Unsubscribe() || kindSub.Active() {
+		t.Fatal("kind subscription must be unsubscribed")
+	}
+	newKindSub, _ := dispatcher.AddKindListener(eventType, 1, add)
+	if kindSub.Unsubscribe() || !newKindSub.Active() {
+		t.Fatal("old subscription must not affect new listener")
+	}
+
+	UnsubscribeAll(newKindSub, valueSub)
+	value = 0
+	dispatcher.Dispatch(testEventID{eventType, eventVal}, nil)
+	if value != 0 {
+		t.Fatal("value must be", 0)
+	}
+	if len(dispatcher.loadKindListenerContainers()) != 0 {
+		t.Fatal("kind listener containers must be empty")
+	}
+}
+
+func TestExpiredSubscription(t *testing.T) {
+	clock := newTestClock()
+	dispatcher := NewDispatcher(WithClock[testET, testEV, testLID](clock))
+	concurrent := NewConcurrentDispatcher(WithClock[testET, testEV, testLID](clock))
+	callback := func(e testEvent) error { return nil }
+
+	sub, _ := dispatcher.AddKindListener(1, 1, callback, WithTTL(time.Second))
+	cSub, _ := concurrent.AddValueListener(testEventID{1, 1}, 1, callback, WithTTL(time.Second))
+	clock.Advance(2 * time.Second)
+
+	// 已过期但尚未被移除的监听者可以取消订阅
+	if sub.Active() || !sub.Unsubscribe() || sub.Unsubscribe() {
+		t.Fatal("expired subscription must be unsubscribed once")
+	}
+	if dispatcher.kindListenerContainers[1] != nil || len(dispatcher.index.listeners) != 0 {
+		t.Fatal("expired listener must be removed")
+	}
+	if cSub.Active() || !cSub.Unsubscribe() || cSub.Unsubscribe() {
+		t.Fatal("expired subscription must be unsubscribed once")
+	}
+	if len(concurrent.loadKindListenerContainers()) != 0 {
+		t.Fatal("expired listener must be removed")
+	}
+}
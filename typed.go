@@ -0,0 +1,253 @@
+package gevent
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ParamTypeError 事件参数类型错误
+// 类型化监听者接收到的事件参数与期望的类型不符时返回
+type ParamTypeError struct {
+	Expected reflect.Type // 期望的参数类型
+	Actual   reflect.Type // 实际的参数类型，参数为 nil 时为空
+}
+
+func (e *ParamTypeError) Error() string {
+	return fmt.Sprintf("event param type mismatch: expected %v, got %v", e.Expected, e.Actual)
+}
+
+// GeneratorTypeError 事件产生者类型错误
+// 通过 GeneratorOf 获取的、或类型化监听者接收到的事件产生者与期望的类型不符时返回
+type GeneratorTypeError struct {
+	Expected reflect.Type // 期望的产生者类型
+	Actual   reflect.Type // 实际的产生者类型，产生者为 nil 时为空
+}
+
+func (e *GeneratorTypeError) Error() string {
+	return fmt.Sprintf("event generator type mismatch: expected %v, got %v", e.Expected, e.Actual)
+}
+
+// TypedListenerCallback 类型化的监听者回调
+// 事件参数及事件产生者已分别被断言为 Param、Generator 类型
+type TypedListenerCallback[EventKind, EventValue comparable, Param, Generator any] func(Event[EventKind, EventValue], Param, Generator) error
+
+// TypedCallback 将类型化的监听者回调转换为 ListenerCallback
+// 事件参数类型不符时，不调用 callback，返回 *ParamTypeError；事件产生者类型不符时，返回 *GeneratorTypeError
+// 参数或产生者为 nil 时，若其类型为接口、指针等可以为 nil 的类型，以零值调用 callback
+func TypedCallback[EventKind, EventValue comparable, Param, Generator any](callback TypedListenerCallback[EventKind, EventValue, Param, Generator]) ListenerCallback[EventKind, EventValue] {
+	if callback == nil {
+		panic("listener callback nil")
+	}
+	return func(evt Event[EventKind, EventValue]) error {
+		param, err := ParamOf[Param](evt)
+		if err != nil {
+			return err
+		}
+		generator, err := GeneratorOf[Generator](evt)
+		if err != nil {
+			return err
+		}
+		return callback(evt, param, generator)
+	}
+}
+
+// ParamOf 将事件参数断言为 Param 类型
+// 类型不符时返回 *ParamTypeError
+func ParamOf[Param any, EventKind, EventValue comparable](evt Event[EventKind, EventValue]) (Param, error) {
+	param, expected, ok := assertType[Param](evt.param)
+	if !ok {
+		return param, &ParamTypeError{Expected: expected, Actual: reflect.TypeOf(evt.param)}
+	}
+	return param, nil
+}
+
+// GeneratorOf 将事件产生者断言为 Generator 类型
+// 供未经 TypedCallback 转换的监听者回调使用
+// 类型不符时返回 *GeneratorTypeError
+func GeneratorOf[Generator any, EventKind, EventValue comparable](evt Event[EventKind, EventValue]) (Generator, error) {
+	generator, expected, ok := assertType[Generator](evt.generator)
+	if !ok {
+		return generator, &GeneratorTypeError{Expected: expected, Actual: reflect.TypeOf(evt.generator)}
+	}
+	return generator, nil
+}
+
+// assertType 将 v 断言为 T 类型
+// 类型不符时返回 T 的零值、期望的类型以及 false
+func assertType[T any](v interface{}) (T, reflect.Type, bool) {
+	if t, ok := v.(T); ok {
+		return t, nil, true
+	}
+	var zero T
+	expected := reflect.TypeOf(&zero).Elem()
+	if v == nil && nillable(expected) {
+		return zero, nil, true
+	}
+	return zero, expected, false
+}
+
+// nillable 返回类型的值是否可以为 nil
+func nillable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return true
+	default:
+		return false
+	}
+}
+
+// TypedDispatcher 类型化的事件派发器
+// 在 Dispatcher 的基础上固定事件参数和事件产生者的类型，派发和监听时均无需进行类型断言
+type TypedDispatcher[EventKind, EventValue, ListenerID comparable, Param, Generator any] struct {
+	dispatcher *Dispatcher[EventKind, EventValue, ListenerID]
+}
+
+func NewTypedDispatcher[EventKind, EventValue, ListenerID comparable, Param, Generator any](opts ...DispatcherOption[EventKind, EventValue, ListenerID]) *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator] {
+	return &TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]{
+		dispatcher: NewDispatcher(opts...),
+	}
+}
+
+// Dispatcher 返回底层的事件派发器
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) Dispatcher() *Dispatcher[EventKind, EventValue, ListenerID] {
+	return d.dispatcher
+}
+
+// AddKindListener 添加事件类型监听者
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) AddKindListener(evtKind EventKind, lID ListenerID, callback TypedListenerCallback[EventKind, EventValue, Param, Generator], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return d.dispatcher.AddKindListener(evtKind, lID, TypedCallback(callback), opts...)
+}
+
+// AddValueListener 添加值类型监听者
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) AddValueListener(evtId EventID[EventKind, EventValue], lID ListenerID, callback TypedListenerCallback[EventKind, EventValue, Param, Generator], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return d.dispatcher.AddValueListener(evtId, lID, TypedCallback(callback), opts...)
+}
+
+// AddKindListenerContext 添加与 ctx 绑定的事件类型监听者
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) AddKindListenerContext(ctx context.Context, evtKind EventKind, lID ListenerID, callback TypedListenerCallback[EventKind, EventValue, Param, Generator], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return d.dispatcher.AddKindListenerContext(ctx, evtKind, lID, TypedCallback(callback), opts...)
+}
+
+// AddValueListenerContext 添加与 ctx 绑定的值类型监听者
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) AddValueListenerContext(ctx context.Context, evtId EventID[EventKind, EventValue], lID ListenerID, callback TypedListenerCallback[EventKind, EventValue, Param, Generator], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return d.dispatcher.AddValueListenerContext(ctx, evtId, lID, TypedCallback(callback), opts...)
+}
+
+// AddFilteredListener 添加带过滤器的事件类型监听者
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) AddFilteredListener(evtKind EventKind, lID ListenerID, filter EventFilter[EventKind, EventValue], callback TypedListenerCallback[EventKind, EventValue, Param, Generator], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return d.dispatcher.AddFilteredListener(evtKind, lID, filter, TypedCallback(callback), opts...)
+}
+
+// AddGlobalListener 添加全局监听者，监听所有类型的事件
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) AddGlobalListener(lID ListenerID, callback TypedListenerCallback[EventKind, EventValue, Param, Generator], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return d.dispatcher.AddGlobalListener(lID, TypedCallback(callback), opts...)
+}
+
+// AddMultiKindListener 添加多类型监听者，监听 evtKinds 中所有类型的事件
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) AddMultiKindListener(evtKinds []EventKind, lID ListenerID, callback TypedListenerCallback[EventKind, EventValue, Param, Generator], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return d.dispatcher.AddMultiKindListener(evtKinds, lID, TypedCallback(callback), opts...)
+}
+
+// RemKindListener 移除事件类型监听者
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) RemKindListener(evtKind EventKind, lID ListenerID) bool {
+	return d.dispatcher.RemKindListener(evtKind, lID)
+}
+
+// RemValueListener 移除值类型监听者
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) RemValueListener(evtId EventID[EventKind, EventValue], lID ListenerID) bool {
+	return d.dispatcher.RemValueListener(evtId, lID)
+}
+
+// RemGlobalListener 移除全局监听者
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) RemGlobalListener(lID ListenerID) bool {
+	return d.dispatcher.RemGlobalListener(lID)
+}
+
+// RemMultiKindListener 移除多类型监听者
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) RemMultiKindListener(lID ListenerID) bool {
+	return d.dispatcher.RemMultiKindListener(lID)
+}
+
+// RemListener 移除监听者ID对应的所有监听者
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) RemListener(lID ListenerID) bool {
+	return d.dispatcher.RemListener(lID)
+}
+
+// ListenersOf 返回监听者ID对应的所有注册信息
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) ListenersOf(lID ListenerID) []Registration[EventKind, EventValue, ListenerID] {
+	return d.dispatcher.ListenersOf(lID)
+}
+
+// SetParent 设置父派发器，parent 为空时解除链接
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) SetParent(parent *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) error {
+	if parent == nil {
+		return d.dispatcher.SetParent(nil)
+	}
+	return d.dispatcher.SetParent(parent.dispatcher)
+}
+
+// AddCaptureListener 添加捕获监听者
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) AddCaptureListener(evtKind EventKind, lID ListenerID, callback TypedListenerCallback[EventKind, EventValue, Param, Generator], opts ...ListenerOption) (*Subscription[EventKind, EventValue, ListenerID], bool) {
+	return d.dispatcher.AddCaptureListener(evtKind, lID, TypedCallback(callback), opts...)
+}
+
+// RemCaptureListener 移除捕获监听者
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) RemCaptureListener(evtKind EventKind, lID ListenerID) bool {
+	return d.dispatcher.RemCaptureListener(evtKind, lID)
+}
+
+// Clear 清理状态，移除所有监听者
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) Clear() {
+	d.dispatcher.Clear()
+}
+
+// Dispatch 构造事件，派发给 evtID 指定的监听者们
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) Dispatch(evtId EventID[EventKind, EventValue], generator Generator, param Param) error {
+	return d.dispatcher.Dispatch(evtId, generator, param)
+}
+
+// DispatchCancelable 构造事件，派发给 evtID 指定的监听者们，返回事件是否被取消
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) DispatchCancelable(evtId EventID[EventKind, EventValue], generator Generator, param Param) (bool, error) {
+	return d.dispatcher.DispatchCancelable(evtId, generator, param)
+}
+
+// SubscribeKind 以 channel 的方式订阅事件类型，返回接收事件的 channel 以及取消订阅的函数
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) SubscribeKind(evtKind EventKind, lID ListenerID, bufSize int, overflow OverflowPolicy, opts ...ListenerOption) (<-chan Event[EventKind, EventValue], func(), bool) {
+	return d.dispatcher.SubscribeKind(evtKind, lID, bufSize, overflow, opts...)
+}
+
+// SubscribeValue 以 channel 的方式订阅事件ID，返回接收事件的 channel 以及取消订阅的函数
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) SubscribeValue(evtId EventID[EventKind, EventValue], lID ListenerID, bufSize int, overflow OverflowPolicy, opts ...ListenerOption) (<-chan Event[EventKind, EventValue], func(), bool) {
+	return d.dispatcher.SubscribeValue(evtId, lID, bufSize, overflow, opts...)
+}
+
+// DispatchSticky 构造事件，保留为粘性事件后派发给 evtID 指定的监听者们
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) DispatchSticky(evtId EventID[EventKind, EventValue], generator Generator, param Param) error {
+	return d.dispatcher.DispatchSticky(evtId, generator, param)
+}
+
+// StickyEvent 返回事件ID对应的粘性事件
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) StickyEvent(evtId EventID[EventKind, EventValue]) (Event[EventKind, EventValue], bool) {
+	return d.dispatcher.StickyEvent(evtId)
+}
+
+// StickyEvents 返回事件类型的所有粘性事件，按照派发顺序排列
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) StickyEvents(evtKind EventKind) []Event[EventKind, EventValue] {
+	return d.dispatcher.StickyEvents(evtKind)
+}
+
+// RemoveSticky 移除事件ID对应的粘性事件
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) RemoveSticky(evtId EventID[EventKind, EventValue]) bool {
+	return d.dispatcher.RemoveSticky(evtId)
+}
+
+// ClearSticky 移除所有粘性事件
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) ClearSticky() {
+	d.dispatcher.ClearSticky()
+}
+
+// Tick 向节流的监听者投递已到期的缓冲事件
+func (d *TypedDispatcher[EventKind, EventValue, ListenerID, Param, Generator]) Tick() error {
+	return d.dispatcher.Tick()
+}
@@ -0,0 +1,112 @@
+package gevent
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testParam struct {
+	value int
+}
+
+func TestTypedDispatcher(t *testing.T) {
+	dispatcher := NewTypedDispatcher[testET, testEV, testLID, *testParam, string]()
+	eventType := testET(1)
+	value := 0
+
+	dispatcher.AddKindListener(eventType, 1, func(e testEvent, p *testParam, generator string) error {
+		if generator != "test" {
+			t.Fatal("generator must be test")
+		}
+		if p != nil {
+			value += p.value
+		}
+		return nil
+	})
+
+	if err := dispatcher.Dispatch(testEventID{eventType, 1}, "test", &testParam{value: 10}); err != nil {
+		t.Fatal("there must no error")
+	}
+	if err := dispatcher.Dispatch(testEventID{eventType, 1}, "test", nil); err != nil {
+		t.Fatal("nil param must be accepted")
+	}
+	if value != 10 {
+		t.Fatal("value must be", 10)
+	}
+
+	// 通过底层派发器派发类型不符的参数
+	err := dispatcher.Dispatcher().Dispatch(testEventID{eventType, 1}, "test", 10)
+	var typeErr *ParamTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatal("error must be ParamTypeError")
+	}
+	// 通过底层派发器派发类型不符的产生者
+	err = dispatcher.Dispatcher().Dispatch(testEventID{eventType, 1}, 1, &testParam{value: 10})
+	var genErr *GeneratorTypeError
+	if !errors.As(err, &genErr) {
+		t.Fatal("error must be GeneratorTypeError")
+	}
+	if value != 10 {
+		t.Fatal("value must be", 10)
+	}
+}
+
+func TestTypedCallback(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+	value := 0
+
+	dispatcher.AddKindListener(eventType, 1, TypedCallback(func(e testEvent, p int, g interface{}) error {
+		value += p
+		return nil
+	}))
+
+	if err := dispatcher.Dispatch(testEventID{eventType, 1}, nil, 5); err != nil {
+		t.Fatal("there must no error")
+	}
+	if value != 5 {
+		t.Fatal("value must be", 5)
+	}
+
+	var typeErr *ParamTypeError
+	if err := dispatcher.Dispatch(testEventID{eventType, 1}, nil, "5"); !errors.As(err, &typeErr) {
+		t.Fatal("error must be ParamTypeError")
+	}
+	if err := dispatcher.Dispatch(testEventID{eventType, 1}, nil); !errors.As(err, &typeErr) {
+		t.Fatal("nil param must be rejected for non-nillable type")
+	}
+	if value != 5 {
+		t.Fatal("value must be", 5)
+	}
+}
+
+func TestGeneratorOf(t *testing.T) {
+	dispatcher := NewDispatcher[testET, testEV, testLID]()
+	eventType := testET(1)
+	var genErr error
+
+	dispatcher.AddKindListener(eventType, 1, func(e testEvent) error {
+		_, genErr = GeneratorOf[string](e)
+		return nil
+	})
+
+	dispatcher.Dispatch(testEventID{eventType, 1}, 10, "param")
+	var typeErr *GeneratorTypeError
+	if !errors.As(genErr, &typeErr) || typeErr.Actual.Kind() != reflect.Int {
+		t.Fatal("error must be GeneratorTypeError")
+	}
+	var paramErr *ParamTypeError
+	if errors.As(genErr, &paramErr) {
+		t.Fatal("error must not be ParamTypeError")
+	}
+
+	dispatcher.Dispatch(testEventID{eventType, 1}, nil, "param")
+	if !errors.As(genErr, &typeErr) || typeErr.Actual != nil {
+		t.Fatal("nil generator must be rejected for non-nillable type")
+	}
+	dispatcher.Dispatch(testEventID{eventType, 1}, "test", 10)
+	if genErr != nil {
+		t.Fatal("there must no error")
+	}
+}